@@ -0,0 +1,307 @@
+package wxgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qingfeng-studio/wxgo/internal/token"
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+const (
+	apiBaseURL = "https://api.weixin.qq.com"
+	// mpBaseURL 二维码图片等公众平台资源的根地址，与 API 域名不同
+	mpBaseURL = "https://mp.weixin.qq.com"
+
+	errCodeInvalidCredential = 40001
+	errCodeTokenExpired      = 42001
+)
+
+// APIGet 以 GET 方式调用任意需要 access_token 的微信接口
+// path 为相对 BaseURL 的路径（如 /cgi-bin/user/info）；errcode 非 0 时返回 *APIError，成功时解析到 out（可为 nil）
+// 用于 SDK 尚未封装的接口
+func (c *Client) APIGet(ctx context.Context, path string, query url.Values, out any) (Code, error) {
+	return c.getJSON(ctx, normalizePath(path), query, out)
+}
+
+// APIPost 以 POST JSON 方式调用任意需要 access_token 的微信接口
+// body 会被序列化为 JSON；其余行为同 APIGet
+func (c *Client) APIPost(ctx context.Context, path string, body any, out any) (Code, error) {
+	return c.postJSON(ctx, normalizePath(path), body, out)
+}
+
+// signURLHosts SignURL 允许附加 access_token 的微信 API 域名（另含 *.api.weixin.qq.com 区域域名）
+var signURLHosts = map[string]bool{
+	"api.weixin.qq.com":   true,
+	"api2.weixin.qq.com":  true,
+	"qyapi.weixin.qq.com": true,
+}
+
+// SignURL 为微信 API 地址附加有效的 access_token
+// 保留原有查询参数与 fragment；已带 access_token 时替换为当前 token，不会重复追加
+// Code 同 GetAccessToken：CodeCacheSet 时仍返回签名后的地址，同时返回写缓存的 error
+// 用于 SDK 尚未封装、需要调用方自行请求的接口（如素材下载链接）
+// 为防止 token 泄漏，仅接受 https 的微信 API 域名（api.weixin.qq.com 及其区域域名、api2、qyapi）或与 BaseURL 相同的地址，否则返回 CodeInvalidParam
+func (c *Client) SignURL(ctx context.Context, rawURL string) (string, Code, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", CodeInvalidParam, fmt.Errorf("parse url %s: %w", transport.RedactURL(rawURL), err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", CodeInvalidParam, fmt.Errorf("url must be absolute: %s", transport.RedactURL(rawURL))
+	}
+	if !c.signableURL(u) {
+		return "", CodeInvalidParam, fmt.Errorf("refusing to sign %s: only https WeChat API hosts or the configured BaseURL are allowed", transport.RedactURL(rawURL))
+	}
+
+	tk, code, err := c.GetAccessToken(ctx)
+	if tk == "" {
+		return "", code, err
+	}
+
+	u.RawQuery = withAccessToken(u.RawQuery, tk)
+	return u.String(), code, err
+}
+
+// signableURL u 是否可以附加 access_token：与 BaseURL 的 scheme 和 host 相同，或为 https 的微信 API 域名
+func (c *Client) signableURL(u *url.URL) bool {
+	if base, err := url.Parse(c.baseURL); err == nil && strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host) {
+		return true
+	}
+	if !strings.EqualFold(u.Scheme, "https") || (u.Port() != "" && u.Port() != "443") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return signURLHosts[host] || strings.HasSuffix(host, ".api.weixin.qq.com")
+}
+
+// withAccessToken 去掉原有的 access_token 参数后追加新值，其余参数保持原顺序与编码
+func withAccessToken(rawQuery, accessToken string) string {
+	parts := make([]string, 0, strings.Count(rawQuery, "&")+2)
+	for _, p := range strings.Split(rawQuery, "&") {
+		if p == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(p, "=")
+		if k, err := url.QueryUnescape(key); err == nil && k == "access_token" {
+			continue
+		}
+		parts = append(parts, p)
+	}
+	parts = append(parts, "access_token="+url.QueryEscape(accessToken))
+	return strings.Join(parts, "&")
+}
+
+// getJSON 以 GET 方式调用需要 access_token 的微信接口，并解析 JSON 响应到 out
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) (Code, error) {
+	return c.callJSON(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// postJSON 以 POST JSON 方式调用需要 access_token 的微信接口，并解析 JSON 响应到 out
+func (c *Client) postJSON(ctx context.Context, path string, body any, out any) (Code, error) {
+	return c.callJSON(ctx, http.MethodPost, path, nil, body, out)
+}
+
+// callJSON 统一处理 token 注入、HTTP 调用与 errcode 解析
+// out 为 nil 时只检查 errcode
+func (c *Client) callJSON(ctx context.Context, method, path string, query url.Values, body any, out any) (Code, error) {
+	var raw []byte
+	contentType := ""
+	if body != nil {
+		var err error
+		raw, err = json.Marshal(body)
+		if err != nil {
+			return CodeUnknown, fmt.Errorf("marshal %s request: %w", path, err)
+		}
+		contentType = "application/json"
+	}
+
+	resp, code, err := c.doAPI(ctx, method, path, query, contentType, raw)
+	if err != nil {
+		return code, err
+	}
+
+	if code, err := decodeAPIResponse(path, resp.body, out); err != nil {
+		return code, err
+	}
+	return code, nil
+}
+
+// apiResponse 已完整读取的微信接口响应
+type apiResponse struct {
+	body   []byte
+	header http.Header
+}
+
+// doAPI 注入 access_token 后发起请求，返回已读取的 2xx 响应（errcode 由调用方解析）
+// 若微信返回 40001/42001（token 失效）且开启了 AutoRefreshOnInvalidToken，
+// 则强制刷新 token 后重放请求一次；重试仅一次，避免死循环
+// 响应体受 MaxResponseBytes 限制
+// 获取 token 返回 CodeCacheSet 等附带可用 token 的错误时照常发送请求，成功后返回该 Code（error 为 nil），否则为 CodeOK
+func (c *Client) doAPI(ctx context.Context, method, path string, query url.Values, contentType string, body []byte) (*apiResponse, Code, error) {
+	return c.doAPIWithLimit(ctx, method, path, query, contentType, bytesBody(body), c.cfg.MaxResponseBytes)
+}
+
+// doMediaAPI 同 doAPI，用于可能返回二进制文件的接口，响应体受 MaxMediaBytes 限制
+func (c *Client) doMediaAPI(ctx context.Context, method, path string, query url.Values, contentType string, body []byte) (*apiResponse, Code, error) {
+	return c.doAPIWithLimit(ctx, method, path, query, contentType, bytesBody(body), c.maxMediaBytes())
+}
+
+// doUploadAPI 同 doAPI，以 POST 发送流式请求体（如大文件上传），重放时重新打开 body
+func (c *Client) doUploadAPI(ctx context.Context, path string, query url.Values, contentType string, body *requestBody) (*apiResponse, Code, error) {
+	return c.doAPIWithLimit(ctx, http.MethodPost, path, query, contentType, body, c.cfg.MaxResponseBytes)
+}
+
+// requestBody 请求体来源；每次发送调用 open 获取从头读取的 reader，token 失效重试与传输层重试时据此重放
+type requestBody struct {
+	open func() (io.Reader, error)
+	// size 请求体字节数，<0 表示未知（以 chunked 方式发送）
+	size int64
+}
+
+// bytesBody 以内存数据作为请求体，data 为 nil 时返回 nil（无请求体）
+func bytesBody(data []byte) *requestBody {
+	if data == nil {
+		return nil
+	}
+	return &requestBody{
+		open: func() (io.Reader, error) { return bytes.NewReader(data), nil },
+		size: int64(len(data)),
+	}
+}
+
+// maxMediaBytes 返回二进制下载的大小上限，默认 32MB
+func (c *Client) maxMediaBytes() int64 {
+	if c.cfg.MaxMediaBytes <= 0 {
+		return transport.DefaultMaxMediaBytes
+	}
+	return c.cfg.MaxMediaBytes
+}
+
+// doAPIWithLimit doAPI 的实现，limit 为响应体大小上限（<=0 时使用默认 10MB）
+func (c *Client) doAPIWithLimit(ctx context.Context, method, path string, query url.Values, contentType string, body *requestBody, limit int64) (*apiResponse, Code, error) {
+	ctx, cancel := transport.WithDefaultTimeout(ctx, c.cfg.DefaultTimeout)
+	defer cancel()
+
+	// 调用方经 WithAccessToken 提供的 token 不由本 Client 管理，失效时不刷新重试
+	if tk, ok := c.contextAccessToken(ctx); ok {
+		return c.sendAPI(ctx, method, path, query, contentType, body, tk, limit)
+	}
+
+	tk, tokenCode, err := c.token.GetAccessToken(ctx)
+	if tk == "" {
+		return nil, tokenCode, err
+	}
+
+	resp, code, err := c.sendAPI(ctx, method, path, query, contentType, body, tk, limit)
+	if err != nil {
+		return nil, code, err
+	}
+	if !c.autoRefreshOnInvalidToken() || !isInvalidTokenResponse(resp) {
+		return resp, tokenCode, nil
+	}
+
+	tk, tokenCode, err = c.token.ForceRefresh(ctx, tk)
+	if tk == "" {
+		return nil, tokenCode, err
+	}
+	resp, code, err = c.sendAPI(ctx, method, path, query, contentType, body, tk, limit)
+	if err != nil {
+		return nil, code, err
+	}
+	return resp, tokenCode, nil
+}
+
+// sendAPI 使用指定 token 发送一次请求并读取响应（最多 limit 字节）
+func (c *Client) sendAPI(ctx context.Context, method, path string, query url.Values, contentType string, body *requestBody, accessToken string, limit int64) (*apiResponse, Code, error) {
+	params := url.Values{}
+	for k, v := range query {
+		params[k] = v
+	}
+	params.Set("access_token", accessToken)
+	reqURL := c.baseURL + path + "?" + params.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		var err error
+		if reader, err = body.open(); err != nil {
+			return nil, CodeUnknown, fmt.Errorf("open %s request body: %w", path, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return nil, CodeHTTP, fmt.Errorf("create %s request: %w", path, transport.RedactURLError(err))
+	}
+	if body != nil && body.size != 0 {
+		req.ContentLength = body.size
+		req.GetBody = func() (io.ReadCloser, error) {
+			r, err := body.open()
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(r), nil
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(ctx, req)
+	if err != nil {
+		return nil, token.RequestErrorCode(ctx, err), fmt.Errorf("request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		c.http.Tap(req, resp.StatusCode, nil)
+		return nil, CodeHTTP, &transport.StatusError{Target: path, StatusCode: resp.StatusCode}
+	}
+
+	data, err := transport.ReadBody(resp.Body, limit)
+	if err != nil {
+		return nil, token.ReadErrorCode(ctx, err, CodeInvalidResponse), fmt.Errorf("read %s response: %w", path, err)
+	}
+	c.http.Tap(req, resp.StatusCode, data)
+
+	return &apiResponse{body: data, header: resp.Header}, CodeOK, nil
+}
+
+// decodeAPIResponse 检查微信返回的 errcode，成功时解析到 out；分类规则见 token.DecodeWeChatResponse
+// 微信业务错误原样返回 *APIError，其余解析错误附带接口路径
+func decodeAPIResponse(path string, data []byte, out any) (Code, error) {
+	code, err := token.DecodeWeChatResponse(data, out)
+	if err != nil && code != CodeAPIError {
+		return code, fmt.Errorf("decode %s response: %w", path, err)
+	}
+	return code, err
+}
+
+// normalizePath 确保路径以 / 开头
+func normalizePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
+
+// isInvalidTokenResponse 判断响应是否为 access_token 失效（40001）或过期（42001）
+// 与 isJSONResponse 一致地去掉 BOM 与首尾空白后再解析，否则带 BOM 的 40001 会跳过刷新重试
+func isInvalidTokenResponse(resp *apiResponse) bool {
+	if !isJSONResponse(resp.header.Get("Content-Type"), resp.body) {
+		return false
+	}
+	var apiResp struct {
+		ErrCode int `json:"errcode"`
+	}
+	if err := json.Unmarshal(transport.TrimJSONBody(resp.body), &apiResp); err != nil {
+		return false
+	}
+	return apiResp.ErrCode == errCodeInvalidCredential || apiResp.ErrCode == errCodeTokenExpired
+}
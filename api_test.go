@@ -0,0 +1,143 @@
+package wxgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo"
+	"github.com/qingfeng-studio/wxgo/wxtest"
+)
+
+func TestSignURLHosts(t *testing.T) {
+	srv := wxtest.NewServer()
+	defer srv.Close()
+	c, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://api.weixin.qq.com/cgi-bin/material/get?media_id=1", true},
+		{"https://sh.api.weixin.qq.com/cgi-bin/user/info", true},
+		{"https://qyapi.weixin.qq.com/cgi-bin/user/get", true},
+		{"https://API.weixin.qq.com:443/cgi-bin/x", true},
+		{srv.URL + "/cgi-bin/anything", true},
+		{"http://api.weixin.qq.com/cgi-bin/x", false},
+		{"https://api.weixin.qq.com:8443/cgi-bin/x", false},
+		{"https://example.com/cgi-bin/x", false},
+		{"https://api.weixin.qq.com.evil.com/cgi-bin/x", false},
+		{"ftp://api.weixin.qq.com/x", false},
+		{"/cgi-bin/relative", false},
+	}
+	for _, tt := range tests {
+		signed, code, err := c.SignURL(context.Background(), tt.url)
+		if tt.ok {
+			if err != nil || !strings.Contains(signed, "access_token="+wxtest.DefaultAccessToken) {
+				t.Errorf("SignURL(%q) = %q, %v; want signed", tt.url, signed, err)
+			}
+			continue
+		}
+		if err == nil || code != wxgo.CodeInvalidParam {
+			t.Errorf("SignURL(%q) = %q, code=%v err=%v; want CodeInvalidParam", tt.url, signed, code, err)
+		}
+	}
+}
+
+// failingSetCache 写入总是失败的缓存，读取与删除委托给内存缓存
+type failingSetCache struct {
+	*wxgo.MemoryCache
+}
+
+func (failingSetCache) Set(context.Context, string, *wxgo.TokenInfo, time.Duration) error {
+	return errors.New("cache unavailable")
+}
+
+func TestAPICallSurfacesCacheSetFailure(t *testing.T) {
+	var apiCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cgi-bin/token" {
+			_, _ = w.Write([]byte(`{"access_token":"tk","expires_in":7200}`))
+			return
+		}
+		apiCalls.Add(1)
+		if got := r.URL.Query().Get("access_token"); got != "tk" {
+			t.Errorf("access_token = %q, want tk", got)
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"value":"v"}`))
+	}))
+	defer srv.Close()
+
+	c, err := wxgo.NewClient(wxgo.Config{
+		AppID:     "wx_cacheset",
+		AppSecret: "secret",
+		BaseURL:   srv.URL,
+		Cache:     failingSetCache{wxgo.NewMemoryCache()},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	code, err := c.APIGet(context.Background(), "/cgi-bin/test", nil, &out)
+	if err != nil || code != wxgo.CodeCacheSet {
+		t.Fatalf("APIGet: code=%v err=%v, want CodeCacheSet without error", code, err)
+	}
+	if apiCalls.Load() != 1 || out.Value != "v" {
+		t.Fatalf("API called %d times, value %q; want the call to complete", apiCalls.Load(), out.Value)
+	}
+
+	signed, code, err := c.SignURL(context.Background(), srv.URL+"/cgi-bin/media/get")
+	if code != wxgo.CodeCacheSet || err == nil || !strings.Contains(signed, "access_token=tk") {
+		t.Fatalf("SignURL = %q, code=%v err=%v; want signed URL with CodeCacheSet", signed, code, err)
+	}
+}
+
+func TestInvalidTokenResponseWithBOMRefreshes(t *testing.T) {
+	var fetches, apiCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cgi-bin/token" {
+			n := fetches.Add(1)
+			_, _ = fmt.Fprintf(w, `{"access_token":"tk-%d","expires_in":7200}`, n)
+			return
+		}
+		apiCalls.Add(1)
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Query().Get("access_token") == "tk-1" {
+			_, _ = w.Write([]byte("\ufeff" + `{"errcode":40001,"errmsg":"invalid credential"}` + "\n"))
+			return
+		}
+		_, _ = w.Write([]byte("\ufeff" + `{"errcode":0,"value":"v"}`))
+	}))
+	defer srv.Close()
+
+	c, err := wxgo.NewClient(wxgo.Config{AppID: "wx_bom", AppSecret: "secret", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	code, err := c.APIGet(context.Background(), "/cgi-bin/test", nil, &out)
+	if err != nil || code != wxgo.CodeOK || out.Value != "v" {
+		t.Fatalf("APIGet: code=%v err=%v value=%q", code, err, out.Value)
+	}
+	if fetches.Load() != 2 || apiCalls.Load() != 2 {
+		t.Fatalf("token fetched %d times, API called %d times; want a refresh and one retry", fetches.Load(), apiCalls.Load())
+	}
+}
@@ -0,0 +1,43 @@
+package wxgo
+
+import "github.com/qingfeng-studio/wxgo/internal/token"
+
+// MemoryCache 进程内缓存（默认缓存），可显式创建后传入 Config.Cache，以便在测试中调用 Reset 清空
+type MemoryCache = token.MemoryCache
+
+// NewMemoryCache 创建内存缓存，用于 Config.Cache；多个 Client 可共享同一实例
+func NewMemoryCache() *MemoryCache {
+	return token.NewMemoryCache()
+}
+
+// FileCache 文件缓存，适用于无 Redis 的单机部署，重启后复用未过期的 token
+type FileCache = token.FileCache
+
+// NewFileCache 创建文件缓存，token 以 JSON 写入 path（权限 0600），用于 Config.Cache
+func NewFileCache(path string) *FileCache {
+	return token.NewFileCache(path)
+}
+
+// TokenCacheKey 返回公众号/小程序 access_token 在缓存中的 key，与 Client 内部使用的格式一致
+// prefix 对应 Config.KeyPrefix，为空时使用默认前缀 wxgo；便于外部脚本读取、预热或删除同一 key
+// 开启 Config.KeyHashTag 时 appID 需传入 "{"+appID+"}"
+// 企业微信、第三方平台与 jsapi_ticket 等凭据的 key 带有凭据名或应用后缀，使用 TokenKeyParts 生成
+func TokenCacheKey(appID, prefix string) string {
+	return token.CacheKey(appID, prefix)
+}
+
+// TokenLockKey 返回刷新 access_token 时使用的分布式锁 key，prefix 规则同 TokenCacheKey
+func TokenLockKey(appID, prefix string) string {
+	return token.LockKey(appID, prefix)
+}
+
+// TokenKeyParts 缓存/锁 key 的组成部分，CacheKey、LockKey 方法返回与 Client 内部一致的 key：
+//   - 第三方平台：Credential 为 component_token
+//   - jsapi_ticket/wx_card ticket：Credential 为 jsapi_ticket/wx_card_ticket
+//   - 企业微信：AppID 为 corpid，Scope 为 AgentID；未设置 AgentID 时为 TokenKeyScope(CorpSecret)
+type TokenKeyParts = token.KeyParts
+
+// TokenKeyScope 返回企业微信未设置 AgentID 时 key 中区分应用的 secret 指纹
+func TokenKeyScope(secret string) string {
+	return token.SecretScope(secret)
+}
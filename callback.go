@@ -0,0 +1,195 @@
+package wxgo
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo/crypto"
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+const (
+	// maxCallbackBodyBytes 回调请求体大小上限；微信推送的消息通常只有几 KB
+	maxCallbackBodyBytes int64 = 1 << 20
+	// maxCallbackSkew 推送请求 timestamp 与当前时间的最大偏差，超出视为重放
+	maxCallbackSkew = 5 * time.Minute
+)
+
+// InboundMessage 微信推送到回调地址的消息或事件（已解密）
+// 只解析常用字段，其余字段可从 Raw 自行解析
+type InboundMessage struct {
+	ToUserName   string `xml:"ToUserName"`
+	FromUserName string `xml:"FromUserName"`
+	CreateTime   int64  `xml:"CreateTime"`
+	// MsgType 消息类型：text/image/voice/video/shortvideo/location/link/event
+	MsgType string `xml:"MsgType"`
+	MsgID   int64  `xml:"MsgId"`
+
+	Content      string  `xml:"Content"`
+	PicURL       string  `xml:"PicUrl"`
+	MediaID      string  `xml:"MediaId"`
+	Format       string  `xml:"Format"`
+	Recognition  string  `xml:"Recognition"`
+	ThumbMediaID string  `xml:"ThumbMediaId"`
+	LocationX    float64 `xml:"Location_X"`
+	LocationY    float64 `xml:"Location_Y"`
+	Scale        int     `xml:"Scale"`
+	Label        string  `xml:"Label"`
+	Title        string  `xml:"Title"`
+	Description  string  `xml:"Description"`
+	URL          string  `xml:"Url"`
+
+	// Event 事件类型（MsgType 为 event 时）：subscribe/unsubscribe/SCAN/LOCATION/CLICK/VIEW 等
+	Event string `xml:"Event"`
+	// EventKey 事件 KEY：扫码事件为 qrscene_ 前缀加场景值（关注时）或场景值，菜单事件为按钮 key/URL
+	EventKey  string  `xml:"EventKey"`
+	Ticket    string  `xml:"Ticket"`
+	Latitude  float64 `xml:"Latitude"`
+	Longitude float64 `xml:"Longitude"`
+	Precision float64 `xml:"Precision"`
+
+	// Raw 解密后的消息 XML 原文
+	Raw []byte `xml:"-"`
+}
+
+// encryptedEnvelope 安全模式下的请求/回复外层 XML
+type encryptedEnvelope struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName,omitempty"`
+	Encrypt      string   `xml:"Encrypt"`
+	MsgSignature string   `xml:"MsgSignature,omitempty"`
+	TimeStamp    string   `xml:"TimeStamp,omitempty"`
+	Nonce        string   `xml:"Nonce,omitempty"`
+}
+
+// NewCallbackHandler 返回可直接挂到 ServeMux 的公众号回调 http.Handler
+// GET：校验签名后原样返回 echostr，完成服务器地址验证；
+// POST：安全模式（带 Encrypt）校验 msg_signature 并解密，明文模式校验 signature，解析消息后调用 handler；
+// handler 返回的 reply 为回复消息的明文 XML，安全模式下加密后返回；reply 为空时返回空的 200，微信不再重试。
+// 签名错误或 POST 的 timestamp 与当前时间相差超过 5 分钟（疑似重放）返回 403，请求体无法解析返回 400，
+// handler 返回 error 时返回 500（微信会重试推送，注意按 MsgId 去重）
+// 使用系统时间与随机串；需要注入 Clock/NonceFunc 时改用 Client.CallbackHandler
+func NewCallbackHandler(mc *crypto.MsgCrypto, handler func(ctx context.Context, msg InboundMessage) (reply []byte, err error)) http.Handler {
+	return &callbackHandler{crypto: mc, handler: handler, now: time.Now, nonce: randomNonce}
+}
+
+// CallbackHandler 同 NewCallbackHandler，时间戳校验与加密回复的时间戳、随机串取自 Config.Clock 与 Config.NonceFunc
+func (c *Client) CallbackHandler(mc *crypto.MsgCrypto, handler func(ctx context.Context, msg InboundMessage) (reply []byte, err error)) http.Handler {
+	return &callbackHandler{crypto: mc, handler: handler, now: c.now, nonce: c.nonce}
+}
+
+// callbackHandler NewCallbackHandler 的实现
+type callbackHandler struct {
+	crypto  *crypto.MsgCrypto
+	handler func(ctx context.Context, msg InboundMessage) ([]byte, error)
+	now     func() time.Time
+	nonce   func() string
+}
+
+func (h *callbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	timestamp, nonce := q.Get("timestamp"), q.Get("nonce")
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := h.crypto.VerifySignature(q.Get("signature"), timestamp, nonce, ""); err != nil {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(q.Get("echostr")))
+		return
+	case http.MethodPost:
+		if !h.freshTimestamp(timestamp) {
+			http.Error(w, "stale timestamp", http.StatusForbidden)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := transport.ReadBody(r.Body, maxCallbackBodyBytes)
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+
+	var envelope encryptedEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "invalid xml", http.StatusBadRequest)
+		return
+	}
+
+	encrypted := envelope.Encrypt != ""
+	plain := body
+	if encrypted {
+		if err := h.crypto.VerifySignature(q.Get("msg_signature"), timestamp, nonce, envelope.Encrypt); err != nil {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+		if plain, err = h.crypto.Decrypt(envelope.Encrypt); err != nil {
+			http.Error(w, "decrypt failed", http.StatusBadRequest)
+			return
+		}
+	} else if err := h.crypto.VerifySignature(q.Get("signature"), timestamp, nonce, ""); err != nil {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	var msg InboundMessage
+	if err := xml.Unmarshal(plain, &msg); err != nil {
+		http.Error(w, "invalid message xml", http.StatusBadRequest)
+		return
+	}
+	msg.Raw = plain
+
+	reply, err := h.handler(r.Context(), msg)
+	if err != nil {
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+	if len(reply) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if encrypted {
+		if reply, err = h.encryptReply(reply); err != nil {
+			http.Error(w, "encrypt reply failed", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, _ = w.Write(reply)
+}
+
+// freshTimestamp 请求 timestamp 是否在当前时间前后 maxCallbackSkew 内；签名只保证未被篡改，不能阻止原样重放
+func (h *callbackHandler) freshTimestamp(timestamp string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := h.now().Sub(time.Unix(ts, 0))
+	return skew <= maxCallbackSkew && skew >= -maxCallbackSkew
+}
+
+// encryptReply 加密回复消息并封装为安全模式的回复 XML
+func (h *callbackHandler) encryptReply(reply []byte) ([]byte, error) {
+	cipherText, err := h.crypto.Encrypt(reply)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(h.now().Unix(), 10)
+	nonce := h.nonce()
+	return xml.Marshal(encryptedEnvelope{
+		Encrypt:      cipherText,
+		MsgSignature: h.crypto.Signature(timestamp, nonce, cipherText),
+		TimeStamp:    timestamp,
+		Nonce:        nonce,
+	})
+}
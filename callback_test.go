@@ -0,0 +1,118 @@
+package wxgo_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo"
+	"github.com/qingfeng-studio/wxgo/crypto"
+)
+
+const (
+	callbackToken  = "callback_token"
+	callbackAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	callbackAppID  = "wx_callback"
+)
+
+// fixedClock 始终返回同一时刻的 Clock
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// encryptedPush 构造安全模式推送请求
+func encryptedPush(t *testing.T, mc *crypto.MsgCrypto, ts time.Time) *http.Request {
+	t.Helper()
+	cipherText, err := mc.Encrypt([]byte(`<xml><ToUserName>gh_test</ToUserName><FromUserName>openid</FromUserName><MsgType>text</MsgType><Content>hi</Content></xml>`))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	timestamp, nonce := strconv.FormatInt(ts.Unix(), 10), "push-nonce"
+	q := url.Values{
+		"timestamp":     {timestamp},
+		"nonce":         {nonce},
+		"msg_signature": {mc.Signature(timestamp, nonce, cipherText)},
+		"encrypt_type":  {"aes"},
+	}
+	body := "<xml><ToUserName>gh_test</ToUserName><Encrypt>" + cipherText + "</Encrypt></xml>"
+	return httptest.NewRequest(http.MethodPost, "/callback?"+q.Encode(), strings.NewReader(body))
+}
+
+func TestCallbackHandlerUsesClientClockAndNonce(t *testing.T) {
+	mc, err := crypto.NewMsgCrypto(callbackToken, callbackAppID, callbackAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	client, err := wxgo.NewClient(wxgo.Config{
+		AppID:     callbackAppID,
+		AppSecret: "secret",
+		Mock:      true,
+		Clock:     fixedClock{now},
+		NonceFunc: func() string { return "fixed-nonce" },
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	h := client.CallbackHandler(mc, func(ctx context.Context, msg wxgo.InboundMessage) ([]byte, error) {
+		return []byte("<xml><Content>pong</Content></xml>"), nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, encryptedPush(t, mc, now.Add(-30*time.Second)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var reply struct {
+		Encrypt      string `xml:"Encrypt"`
+		MsgSignature string `xml:"MsgSignature"`
+		TimeStamp    string `xml:"TimeStamp"`
+		Nonce        string `xml:"Nonce"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("decode reply: %v", err)
+	}
+	if reply.TimeStamp != strconv.FormatInt(now.Unix(), 10) || reply.Nonce != "fixed-nonce" {
+		t.Fatalf("reply timestamp/nonce = %s/%s, want the injected Clock/NonceFunc values", reply.TimeStamp, reply.Nonce)
+	}
+	if err := mc.VerifySignature(reply.MsgSignature, reply.TimeStamp, reply.Nonce, reply.Encrypt); err != nil {
+		t.Fatalf("reply signature: %v", err)
+	}
+}
+
+func TestCallbackHandlerRejectsStaleTimestamp(t *testing.T) {
+	mc, err := crypto.NewMsgCrypto(callbackToken, callbackAppID, callbackAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	called := false
+	h := wxgo.NewCallbackHandler(mc, func(ctx context.Context, msg wxgo.InboundMessage) ([]byte, error) {
+		called = true
+		return nil, nil
+	})
+
+	for _, offset := range []time.Duration{-10 * time.Minute, 10 * time.Minute} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, encryptedPush(t, mc, time.Now().Add(offset)))
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("offset %s: status = %d, want 403", offset, rec.Code)
+		}
+	}
+	if called {
+		t.Fatal("handler called for a replayed push")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, encryptedPush(t, mc, time.Now()))
+	if rec.Code != http.StatusOK || !called {
+		t.Fatalf("fresh push: status = %d, called = %v", rec.Code, called)
+	}
+}
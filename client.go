@@ -2,29 +2,157 @@ package wxgo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/qingfeng-studio/wxgo/internal/token"
 	"github.com/qingfeng-studio/wxgo/internal/transport"
+	"golang.org/x/sync/singleflight"
 )
 
 // Client 微信 API 客户端
 type Client struct {
-	cfg   Config
-	http  *transport.Client
-	token *token.Manager
+	cfg       Config
+	http      *transport.Client
+	token     *token.Manager
+	baseURL   string
+	mpBaseURL string
+	qrGroup   singleflight.Group // 合并并发的相同永久二维码请求
+
+	tokenCfg    token.Config              // 已校验的 token 配置，ticket 等凭据的 Manager 以此为模板
+	credMu      sync.Mutex                // 保护 credentials 与 closed
+	credentials map[string]*token.Manager // 按 ticket 类型懒创建的 Manager
+	closed      bool
+
+	owned     []io.Closer // SDK 自行创建、由 Close 释放的资源；调用方通过 Config 传入的资源不在其中
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// closerFunc 将普通函数适配为 io.Closer
+type closerFunc func() error
+
+// Close 实现 io.Closer
+func (f closerFunc) Close() error {
+	return f()
 }
 
 // NewClient 创建微信客户端
 func NewClient(cfg Config) (*Client, error) {
+	httpClient := newHTTPClient(cfg)
+	c, err := newClient(cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	// 连接池由 SDK 创建（未传 Transport，或传了 TLSConfig 时基于其克隆）时，Close 时一并关闭空闲连接
+	if !cfg.Mock && (cfg.Transport == nil || cfg.TLSConfig != nil) {
+		c.owned = append(c.owned, closerFunc(func() error {
+			httpClient.CloseIdleConnections()
+			return nil
+		}))
+	}
+	return c, nil
+}
+
+// newHTTPClient 按 Config 中的连接池与出站请求选项创建 transport client
+func newHTTPClient(cfg Config) *transport.Client {
+	httpClient := transport.NewClient()
+	if cfg.HTTPTimeout > 0 {
+		httpClient.SetTimeout(cfg.HTTPTimeout)
+	}
+	if cfg.Transport != nil || cfg.TLSConfig != nil {
+		httpClient.SetTransport(buildTransport(cfg))
+	}
+	if cfg.Mock {
+		httpClient.SetTransport(mockTransport{})
+	}
+	httpClient.SetRetry(transport.RetryPolicy{MaxRetries: cfg.MaxRetries, MaxWait: cfg.MaxRetryWait})
+	httpClient.SetDefaultHeaders(cfg.DefaultHeaders)
+	httpClient.SetUserAgent(cfg.UserAgent)
+	httpClient.SetRequestIDFunc(cfg.RequestIDFunc)
+	httpClient.SetResponseTap(cfg.ResponseTap)
+	return httpClient
+}
+
+// hasHTTPOptions 是否设置了任一连接池或出站请求选项；未设置时可共用 Registry 的连接池
+func (cfg Config) hasHTTPOptions() bool {
+	return cfg.Mock || cfg.Transport != nil || cfg.TLSConfig != nil || cfg.HTTPTimeout > 0 ||
+		cfg.MaxRetries != 0 || cfg.MaxRetryWait != 0 || len(cfg.DefaultHeaders) > 0 ||
+		cfg.UserAgent != "" || cfg.RequestIDFunc != nil || cfg.ResponseTap != nil
+}
+
+// buildTransport 返回 Config 对应的连接池；设置 TLSConfig 时基于 Transport（或默认连接池）克隆后替换 TLS 配置，不修改调用方的 Transport
+func buildTransport(cfg Config) *http.Transport {
+	if cfg.TLSConfig == nil {
+		return cfg.Transport
+	}
+	t := transport.NewTransport()
+	if cfg.Transport != nil {
+		t = cfg.Transport.Clone()
+	}
+	t.TLSClientConfig = cfg.TLSConfig.Clone()
+	return t
+}
+
+// newClient 使用给定的 transport client 创建客户端（Registry 借此共享连接池）
+func newClient(cfg Config, httpClient *transport.Client) (*Client, error) {
+	mpBase := mpBaseURL
+	if cfg.MPBaseURL != "" {
+		u, err := url.Parse(cfg.MPBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid MPBaseURL %q: must be an absolute http(s) URL", cfg.MPBaseURL)
+		}
+		mpBase = strings.TrimRight(cfg.MPBaseURL, "/")
+	}
+
 	// 构建 token 配置
 	tokenConfig := &token.Config{
-		AppID:              cfg.AppID,
-		AppSecret:          cfg.AppSecret,
-		Cache:              cfg.Cache,
-		RedisClient:        cfg.RedisClient,
-		RedisClusterClient: cfg.RedisClusterClient,
-		DistLockStrategy:   cfg.DistLockStrategy,
+		AppID:                       cfg.AppID,
+		AppSecret:                   cfg.AppSecret,
+		SecretProvider:              cfg.SecretProvider,
+		Fetcher:                     cfg.Fetcher,
+		Provider:                    cfg.Provider,
+		CorpID:                      cfg.CorpID,
+		CorpSecret:                  cfg.CorpSecret,
+		AgentID:                     cfg.AgentID,
+		ComponentVerifyTicket:       cfg.ComponentVerifyTicket,
+		StableForceRefreshInterval:  cfg.StableForceRefreshInterval,
+		Cache:                       cfg.Cache,
+		RedisClient:                 cfg.RedisClient,
+		RedisReadClient:             cfg.RedisReadClient,
+		RedisClusterClient:          cfg.RedisClusterClient,
+		LockRedisClient:             cfg.LockRedisClient,
+		LockRedisClusterClient:      cfg.LockRedisClusterClient,
+		PreferRedis:                 cfg.PreferRedis,
+		StrictBackend:               cfg.StrictBackend,
+		DistLockStrategy:            cfg.DistLockStrategy,
+		LockerOptions:               cfg.LockerOptions,
+		LockWaitTimeout:             cfg.LockWaitTimeout,
+		AllowLocalRefreshOnLockFail: cfg.AllowLocalRefreshOnLockFail,
+		BaseURL:                     cfg.BaseURL,
+		Logger:                      cfg.Logger,
+		KeyPrefix:                   cfg.KeyPrefix,
+		KeyHashTag:                  cfg.KeyHashTag,
+		RedisFastPath:               cfg.RedisFastPath,
+		DefaultTimeout:              cfg.DefaultTimeout,
+		TTLJitter:                   cfg.TTLJitter,
+		EarlyRefresh:                cfg.EarlyRefresh,
+		HotCacheTTL:                 cfg.HotCacheTTL,
+		CacheTTLPadding:             cfg.CacheTTLPadding,
+		StaleIfError:                cfg.StaleIfError,
+		BackgroundRefresh:           cfg.BackgroundRefresh,
+		NegativeCache:               cfg.NegativeCache,
+		NegativeCacheTTL:            cfg.NegativeCacheTTL,
+		HTTPClient:                  httpClient,
+		MaxResponseBytes:            cfg.MaxResponseBytes,
+		Metrics:                     cfg.Metrics,
+		Clock:                       cfg.Clock,
 	}
 
 	// 初始化 token manager
@@ -32,31 +160,189 @@ func NewClient(cfg Config) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create token manager: %w", err)
 	}
+	// 与 token manager 使用同一份校验后的凭据（已去除首尾空白，企业微信下已取 CorpID/CorpSecret），SignJSConfig 等直接读取 c.cfg
+	cfg.AppID, cfg.AppSecret = tokenConfig.AppID, tokenConfig.AppSecret
+	if cfg.EarlyRefresh != nil && *cfg.EarlyRefresh == 0 && cfg.AutoRefreshOnInvalidToken != nil && !*cfg.AutoRefreshOnInvalidToken && cfg.Logger != nil {
+		cfg.Logger.Printf("wxgo: EarlyRefresh is 0 and AutoRefreshOnInvalidToken is off; requests near token expiry may fail with 40001")
+	}
 
-	// 初始化 transport client
-	httpClient := transport.NewClient()
-	if cfg.HTTPTimeout > 0 {
-		httpClient.SetTimeout(cfg.HTTPTimeout)
+	baseURL := apiBaseURL
+	if cfg.Provider == ProviderWorkWeChat {
+		baseURL = token.WorkWeChatBaseURL
+	}
+	if cfg.BaseURL != "" {
+		baseURL = strings.TrimRight(cfg.BaseURL, "/")
 	}
 
-	return &Client{
-		cfg:   cfg,
-		http:  httpClient,
-		token: tokenMgr,
-	}, nil
+	c := &Client{
+		cfg:       cfg,
+		http:      httpClient,
+		token:     tokenMgr,
+		baseURL:   baseURL,
+		mpBaseURL: mpBase,
+		tokenCfg:  *tokenConfig,
+	}
+	return c, nil
 }
 
 // GetAccessToken 获取 Access Token，返回值：(token, code, err)
 // code 便于上层做国际化/分支处理；不需要时可用空标识符忽略
+// ctx 经 WithAccessToken 携带本应用的 token 时直接返回它
 func (c *Client) GetAccessToken(ctx context.Context) (string, token.Code, error) {
+	if tk, ok := c.contextAccessToken(ctx); ok {
+		return tk, CodeOK, nil
+	}
 	return c.token.GetAccessToken(ctx)
 }
 
-// authHeader 获取鉴权 Header（供内部 Service 使用）
-func (c *Client) authHeader(ctx context.Context) (string, error) {
-	tk, _, err := c.token.GetAccessToken(ctx)
+// GetComponentAccessToken 获取第三方平台的 component_access_token，返回值：(token, code, err)
+// 仅 Provider 为 ProviderComponent 时可用，缓存、分布式锁与提前刷新逻辑与 access_token 相同；
+// 其他 Provider 返回 CodeInvalidParam 与 ErrNotComponentProvider
+func (c *Client) GetComponentAccessToken(ctx context.Context) (string, Code, error) {
+	if c.cfg.Provider != ProviderComponent {
+		return "", CodeInvalidParam, ErrNotComponentProvider
+	}
+	return c.token.GetAccessToken(ctx)
+}
+
+// InvalidateToken 删除缓存中的 Access Token（内存/Redis/集群均适用），不会向微信重新获取
+// 与 ForceRefreshToken 不同，适用于停机清理或 AppSecret 已轮换但尚未拿到新 secret 的场景
+// 删除失败时返回错误，应视为失败：旧 token 仍在缓存中，其他实例会继续使用
+func (c *Client) InvalidateToken(ctx context.Context) error {
+	return c.token.Invalidate(ctx)
+}
+
+// FlushTokens 删除缓存中当前 KeyPrefix 下所有 AppID 的 token（分布式锁 key 不受影响），适用于管理端"清空所有 token"
+// Redis 以 SCAN 按前缀删除，不会 FLUSHDB；缓存未实现 Flusher 时返回 ErrFlushNotSupported
+func (c *Client) FlushTokens(ctx context.Context) error {
+	return c.token.Flush(ctx)
+}
+
+// TokenExpiry 返回缓存中 token 的过期时间，只读缓存，不会触发微信请求
+// 缓存中没有 token 时返回零值、CodeNotCached 与 ErrTokenNotCached
+func (c *Client) TokenExpiry(ctx context.Context) (time.Time, Code, error) {
+	tk, code, err := c.token.CachedToken(ctx)
+	if err != nil {
+		return time.Time{}, code, err
+	}
+	return tk.ExpiresAt, CodeOK, nil
+}
+
+// TokenExpired 判断 token（如自行从缓存读取的 TokenInfo）是否已过期，与本 Client 决定刷新时的判断一致：
+// 使用 Config.Clock 与 EarlyRefresh（含 TTLJitter）；TokenInfo.IsExpired 固定使用系统时间和 5 分钟窗口
+func (c *Client) TokenExpired(t *TokenInfo) bool {
+	return c.token.Expired(t)
+}
+
+// TokenTTL 返回缓存中 token 的剩余有效时间（已过期为 0），只读缓存
+// 缓存中没有 token 时返回 0、CodeNotCached 与 ErrTokenNotCached
+func (c *Client) TokenTTL(ctx context.Context) (time.Duration, Code, error) {
+	expiresAt, code, err := c.TokenExpiry(ctx)
 	if err != nil {
-		return "", err
+		return 0, code, err
+	}
+	ttl := expiresAt.Sub(c.now())
+	if ttl < 0 {
+		ttl = 0
+	}
+	return ttl, CodeOK, nil
+}
+
+// ForceRefreshToken 跳过缓存，强制从微信获取新的 Access Token 并写入缓存
+// 适用于确认 token 已被服务端作废（如其他系统重新获取了 token）的场景
+func (c *Client) ForceRefreshToken(ctx context.Context) (string, Code, error) {
+	return c.token.ForceRefresh(ctx, "")
+}
+
+// RotateSecret 运行时轮换 AppSecret：替换 secret、删除缓存中的旧 token（内存/Redis/集群）、清除负缓存并立即获取新 token
+// 全程持有分布式锁，多实例同时轮换时串行执行；配置了 SecretProvider 时 newSecret 传空串，仅作废并刷新
+// 删除旧 token 失败不中断轮换：新 token 写入后覆盖旧值，返回 CodeCacheDelete 与错误供观测，可视为非致命
+func (c *Client) RotateSecret(ctx context.Context, newSecret string) (Code, error) {
+	return c.token.RotateSecret(ctx, newSecret)
+}
+
+// Warmup 预热 token 缓存，建议在服务启动、开始接收流量前调用
+// 缓存中已有有效 token 时直接返回；否则向微信获取并写入缓存，多实例同时调用时由分布式锁保证只获取一次
+func (c *Client) Warmup(ctx context.Context) (Code, error) {
+	_, code, err := c.token.GetAccessToken(ctx)
+	return code, err
+}
+
+// Stats 返回运行时累计计数快照：token 获取、缓存命中/未命中、锁获取、微信错误码分布与最近刷新时间
+// 不依赖任何指标系统，适合在管理端点直接输出；与 Config.Metrics 在同一位置更新
+func (c *Client) Stats() ClientStats {
+	return c.token.Stats()
+}
+
+// Close 释放 Client 的全部资源，是唯一的清理入口：停止后台刷新协程（含 jsapi_ticket 等凭据）并等待其退出，
+// 进行中的后台微信请求随之取消；再关闭 SDK 自行创建的资源（默认连接池中的空闲连接）
+// 调用方自行创建并通过 Config 传入的资源归调用方所有，Close 不会关闭：RedisClient/RedisClusterClient、Cache、Transport，
+// 以及 Metrics（如 metrics/prometheus 的 Collector，需要时自行调用其 Unregister）
+// 可重复、并发调用，只执行一次并始终返回首次的结果；Close 之后仍可同步调用接口，但不再后台刷新
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.credMu.Lock()
+		c.closed = true
+		managers := make([]*token.Manager, 0, len(c.credentials))
+		for _, m := range c.credentials {
+			managers = append(managers, m)
+		}
+		c.credMu.Unlock()
+
+		errs := []error{c.token.Close()}
+		for _, m := range managers {
+			errs = append(errs, m.Close())
+		}
+		// 后创建的先释放
+		for i := len(c.owned) - 1; i >= 0; i-- {
+			errs = append(errs, c.owned[i].Close())
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
+
+// Config 返回应用默认值后实际生效的配置（锁 TTL、提前刷新窗口、重试参数、选用的后端与锁策略等），AppSecret 已脱敏
+// 适合在启动时输出日志，确认默认值与配置优先级的实际结果
+func (c *Client) Config() EffectiveConfig {
+	eff := c.token.EffectiveConfig()
+	eff.BaseURL = c.baseURL
+	eff.MPBaseURL = c.mpBaseURL
+	eff.HTTPTimeout = c.http.Timeout()
+	retry := c.http.Retry()
+	eff.MaxRetries = retry.MaxRetries
+	eff.MaxRetryWait = retry.MaxWait
+	eff.AutoRefreshOnInvalidToken = c.autoRefreshOnInvalidToken()
+	return eff
+}
+
+// Backend 返回实际选用的缓存（memory/redis/redis-cluster/custom）与分布式锁后端
+// 同时配置多个缓存来源时按 Cache > RedisClusterClient > RedisClient > 内存 选择其一，适合在启动日志中输出以确认生效的配置
+func (c *Client) Backend() BackendInfo {
+	return c.token.Backend()
+}
+
+// now 返回当前时间，优先使用配置的 Clock
+func (c *Client) now() time.Time {
+	if c.cfg.Clock != nil {
+		return c.cfg.Clock.Now()
+	}
+	return time.Now()
+}
+
+// autoRefreshOnInvalidToken 是否在 token 失效时自动刷新并重试，默认开启
+func (c *Client) autoRefreshOnInvalidToken() bool {
+	return c.cfg.AutoRefreshOnInvalidToken == nil || *c.cfg.AutoRefreshOnInvalidToken
+}
+
+// GetAccessTokenDetailed 获取 Access Token 及详细信息（过期时间、是否命中缓存、结果码）
+// 返回的 *TokenResult 始终非 nil，失败时 Code 为错误码；
+// Code 为 CodeCacheSet 时 token 可用但写缓存失败，AccessToken 与 error 同时返回
+// ctx 经 WithAccessToken 携带本应用的 token 时直接返回它，ExpiresAt 为零值
+func (c *Client) GetAccessTokenDetailed(ctx context.Context) (*TokenResult, error) {
+	if tk, ok := c.contextAccessToken(ctx); ok {
+		// 调用方提供的 token，过期时间未知
+		return &TokenResult{AccessToken: tk, Code: CodeOK}, nil
 	}
-	return "Bearer " + tk, nil
+	return c.token.GetToken(ctx)
 }
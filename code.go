@@ -12,22 +12,135 @@ const (
 	CodeMissingAppID = token.CodeMissingAppID
 	// CodeMissingAppSecret 缺少 AppSecret
 	CodeMissingAppSecret = token.CodeMissingAppSecret
+	// CodeMissingVerifyTicket 第三方平台缺少 component_verify_ticket
+	CodeMissingVerifyTicket = token.CodeMissingVerifyTicket
 	// CodeCacheGet 缓存读取失败
 	CodeCacheGet = token.CodeCacheGet
 	// CodeCacheSet 缓存写入失败
 	CodeCacheSet = token.CodeCacheSet
+	// CodeCacheDelete 缓存删除失败；RotateSecret 返回它时轮换已完成，可视为非致命
+	CodeCacheDelete = token.CodeCacheDelete
 	// CodeHTTP 调用 HTTP 失败
 	CodeHTTP = token.CodeHTTP
 	// CodeAPIError 微信 API 返回错误
 	CodeAPIError = token.CodeAPIError
 	// CodeInvalidResponse 响应解析失败
 	CodeInvalidResponse = token.CodeInvalidResponse
+	// CodeNonJSONResponse 微信返回了非 JSON 响应（如故障期间的 HTML 错误页）
+	CodeNonJSONResponse = token.CodeNonJSONResponse
+	// CodeResponseTooLarge 响应体超过 MaxResponseBytes/MaxMediaBytes 上限
+	CodeResponseTooLarge = token.CodeResponseTooLarge
 	// CodeLock 分布式锁获取失败
 	CodeLock = token.CodeLock
+	// CodeInvalidParam 请求参数不合法（客户端校验未通过）
+	CodeInvalidParam = token.CodeInvalidParam
+	// CodeUserRefused 用户拒绝接收消息（errcode=43101）
+	CodeUserRefused = token.CodeUserRefused
+	// CodeInvalidOpenID openid 不合法或不属于当前 AppID（errcode=40003）
+	CodeInvalidOpenID = token.CodeInvalidOpenID
+	// CodeIPNotWhitelisted 出口 IP 不在公众号 IP 白名单中（errcode=40164）
+	CodeIPNotWhitelisted = token.CodeIPNotWhitelisted
+	// CodeInvalidDateRange 数据统计接口日期范围不合法（errcode=61501）
+	CodeInvalidDateRange = token.CodeInvalidDateRange
+	// CodeStale 获取失败，返回的是缓存中已过期的 token（StaleIfError 兜底）
+	CodeStale = token.CodeStale
+	// CodeForceRefreshThrottled 稳定版 token 强制刷新被节流，已退回普通获取（token 可用，error 为 nil）
+	CodeForceRefreshThrottled = token.CodeForceRefreshThrottled
+	// CodeUnionIDUnavailable 微信未返回 unionid（公众号未绑定到微信开放平台帐号）
+	CodeUnionIDUnavailable = token.CodeUnionIDUnavailable
+	// CodeNotCached 缓存中没有 token
+	CodeNotCached = token.CodeNotCached
+	// CodeCanceled 调用方取消了 ctx
+	CodeCanceled = token.CodeCanceled
+	// CodeTimeout 调用方 ctx 已超过截止时间
+	CodeTimeout = token.CodeTimeout
 	// CodeUnknown 未分类错误
 	CodeUnknown = token.CodeUnknown
 )
 
+// APIError 微信 API 返回的业务错误，可用 errors.As 取出 errcode
+type APIError = token.APIError
+
+var (
+	// ErrAPIError 所有微信业务错误的哨兵值，可用 errors.Is 判断
+	ErrAPIError = token.ErrAPIError
+	// ErrMissingAppID AppID 未设置
+	ErrMissingAppID = token.ErrMissingAppID
+	// ErrMissingAppSecret AppSecret 未设置
+	ErrMissingAppSecret = token.ErrMissingAppSecret
+	// ErrMissingAgentID 企业微信使用 SecretProvider 时未设置 AgentID
+	ErrMissingAgentID = token.ErrMissingAgentID
+	// ErrInvalidTTL 写入缓存的 TTL 非正数，RedisCache/RedisClusterCache 拒绝写入
+	ErrInvalidTTL = token.ErrInvalidTTL
+	// ErrInvalidEarlyRefresh EarlyRefresh 为负数
+	ErrInvalidEarlyRefresh = token.ErrInvalidEarlyRefresh
+	// ErrInvalidHotCacheTTL HotCacheTTL 超出 [0, 1m]
+	ErrInvalidHotCacheTTL = token.ErrInvalidHotCacheTTL
+	// ErrInvalidTTLPadding CacheTTLPadding 超出 [0, 1h]
+	ErrInvalidTTLPadding = token.ErrInvalidTTLPadding
+	// ErrInvalidTTLJitter TTLJitter 超出 [0, 1)
+	ErrInvalidTTLJitter = token.ErrInvalidTTLJitter
+	// ErrReadClientWithoutWriter 设置了 RedisReadClient 但未设置 RedisClient
+	ErrReadClientWithoutWriter = token.ErrReadClientWithoutWriter
+	// ErrInvalidPreferRedis PreferRedis 不是 cluster/single
+	ErrInvalidPreferRedis = token.ErrInvalidPreferRedis
+	// ErrMultipleBackends 开启 StrictBackend 时同时配置了多个缓存来源
+	ErrMultipleBackends = token.ErrMultipleBackends
+	// ErrLockBackendMissing DistLockStrategy 为 on 但没有可用的分布式锁后端；错误信息列出检查过的后端与配置建议
+	ErrLockBackendMissing = token.ErrLockBackendMissing
+	// ErrFastPathNeedsHashTag Redis 集群上开启 RedisFastPath 须同时开启 KeyHashTag
+	ErrFastPathNeedsHashTag = token.ErrFastPathNeedsHashTag
+	// ErrFlushNotSupported 缓存未实现 Flusher，FlushTokens 无法执行
+	ErrFlushNotSupported = token.ErrFlushNotSupported
+	// ErrMissingVerifyTicket 第三方平台未提供 component_verify_ticket
+	ErrMissingVerifyTicket = token.ErrMissingVerifyTicket
+	// ErrNotComponentProvider 当前 Client 不是第三方平台（ProviderComponent）
+	ErrNotComponentProvider = token.ErrNotComponentProvider
+	// ErrInvalidAppID AppID 含空白或控制字符
+	ErrInvalidAppID = token.ErrInvalidAppID
+	// ErrInvalidAppSecret AppSecret 含空白或控制字符
+	ErrInvalidAppSecret = token.ErrInvalidAppSecret
+	// ErrNonJSONResponse 微信返回了非 JSON 响应，通常是微信侧故障
+	ErrNonJSONResponse = token.ErrNonJSONResponse
+	// ErrResponseTooLarge 响应体超过大小上限
+	ErrResponseTooLarge = token.ErrResponseTooLarge
+	// ErrTokenNotCached 缓存中没有 token
+	ErrTokenNotCached = token.ErrTokenNotCached
+)
+
+// Logger 日志接口；*log.Logger 即满足该接口
+type Logger = token.Logger
+
+// Clock 时间源接口，可通过 Config.Clock 注入
+type Clock = token.Clock
+
+// TimerClock 可选接口：Config.Clock 实现它时，后台刷新的等待也由其计时
+type TimerClock = token.TimerClock
+
+// Timer TimerClock 创建的计时器
+type Timer = token.Timer
+
+// Pinger 可选接口：自定义缓存实现它后，PingCache/HealthCheck 会用它探测后端
+type Pinger = token.Pinger
+
+// Flusher 可选接口：自定义缓存实现它后，FlushTokens 可按 key 前缀批量删除 token；内置内存/Redis/集群/文件缓存均已实现
+type Flusher = token.Flusher
+
+// BackendInfo Client 实际选用的缓存与分布式锁后端，见 Client.Backend
+type BackendInfo = token.BackendInfo
+
+// TokenResult 获取 token 的详细结果
+type TokenResult = token.TokenResult
+
+// Metrics 指标回调接口，实现需并发安全
+type Metrics = token.Metrics
+
+// LockHoldObserver 可选接口：Metrics 实现它即可观测分布式锁持有时长与超时（overrun）
+type LockHoldObserver = token.LockHoldObserver
+
+// CredentialMetrics 可选接口：Metrics 实现它即可单独上报 jsapi_ticket、wx_card_ticket 的指标；未实现时这些凭据不上报 Metrics
+type CredentialMetrics = token.CredentialMetrics
+
 // DistLockStrategy 分布式锁策略
 type DistLockStrategy = token.DistLockStrategy
 
@@ -39,3 +152,49 @@ const (
 	// DistLockOff 关闭分布式锁，只用本地互斥
 	DistLockOff = token.DistLockOff
 )
+
+// RedisPreference 同时配置 Redis 单点与集群客户端时的选择
+type RedisPreference = token.RedisPreference
+
+const (
+	// PreferRedisCluster 优先使用 RedisClusterClient（默认）
+	PreferRedisCluster = token.PreferRedisCluster
+	// PreferRedisSingle 优先使用 RedisClient
+	PreferRedisSingle = token.PreferRedisSingle
+)
+
+// TokenInfo 缓存中的 token 信息，自定义 Cache/TokenFetcher 实现时使用
+type TokenInfo = token.TokenInfo
+
+// TokenFetcher 自定义 token 获取实现
+type TokenFetcher = token.TokenFetcher
+
+// ClientStats 运行时累计计数快照
+type ClientStats = token.Stats
+
+// EffectiveConfig 应用默认值后实际生效的配置，由 Client.Config 返回
+type EffectiveConfig = token.EffectiveConfig
+
+// LockerOptions 内置 Redis 分布式锁的重试退避参数
+type LockerOptions = token.LockerOptions
+
+// Provider token 接口提供方
+type Provider = token.Provider
+
+const (
+	// ProviderMP 公众号/小程序（默认）
+	ProviderMP = token.ProviderMP
+	// ProviderWorkWeChat 企业微信（gettoken?corpid=&corpsecret=）
+	ProviderWorkWeChat = token.ProviderWorkWeChat
+	// ProviderComponent 开放平台第三方平台（api_component_token，获取 component_access_token）
+	ProviderComponent = token.ProviderComponent
+	// ProviderStable 公众号/小程序稳定版 access_token（stable_token）
+	ProviderStable = token.ProviderStable
+)
+
+// IsRetryable 判断 SDK 返回的错误是否值得重试，供调用方自行实现重试循环时复用 SDK 的分类
+// 可重试：微信系统繁忙（-1）、调用过于频繁（45011）、HTTP 429/5xx、网络错误、非 JSON 故障页；
+// 不可重试：AppID/AppSecret 错误、IP 白名单、日限额（45009）、未授权（48001）、ctx 取消/超时等
+func IsRetryable(err error) bool {
+	return token.IsRetryable(err)
+}
@@ -1,6 +1,9 @@
 package wxgo
 
 import (
+	"context"
+	"crypto/tls"
+	"net/http"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -15,18 +18,218 @@ type Config struct {
 	// AppSecret 微信公众号/小程序的 AppSecret
 	AppSecret string
 
+	// Provider token 接口提供方：ProviderMP（默认，公众号/小程序）、ProviderStable（稳定版 stable_token）、ProviderWorkWeChat（企业微信）或 ProviderComponent（开放平台第三方平台）
+	// 企业微信下 BaseURL 默认为 https://qyapi.weixin.qq.com，缓存/锁/刷新逻辑与公众号一致
+	// 第三方平台下 AppID/AppSecret 填 component_appid/component_appsecret，须同时设置 ComponentVerifyTicket
+	Provider Provider
+
+	// CorpID 企业微信 corpid；Provider 为 ProviderWorkWeChat 且未填 AppID 时使用
+	CorpID string
+
+	// CorpSecret 企业微信应用的 secret；Provider 为 ProviderWorkWeChat 且未填 AppSecret 时使用
+	CorpSecret string
+
+	// AgentID 企业微信应用的 agentid；同一企业下多个应用共用 corpid，缓存/锁 key 为 {corpid}:{agentid}
+	// 未设置时以 CorpSecret 的指纹代替（各实例须以相同 secret 启动），使用 SecretProvider 时必填
+	AgentID string
+
+	// ComponentVerifyTicket 返回最新的 component_verify_ticket（Provider 为 ProviderComponent 时必填）
+	// 微信约每 10 分钟向第三方平台推送一次新 ticket，通常由接收推送的服务写入 Redis/数据库，此处读取最新值
+	ComponentVerifyTicket func(ctx context.Context) (string, error)
+
+	// StableForceRefreshInterval Provider 为 ProviderStable 时两次 force_refresh 的最小间隔；默认 72 分钟
+	// 微信限制 force_refresh 每天 20 次，上次强制刷新时间以哨兵 key 记录在共享缓存中，多实例共同受限；
+	// 间隔内的 ForceRefreshToken（含 40001 自动重试）退回普通获取，返回 CodeForceRefreshThrottled 与可用 token
+	StableForceRefreshInterval time.Duration
+
+	// Fetcher 自定义 token 获取实现（可选），如从内部集中式 token 服务获取；设置后可不填 AppSecret
+	// 缓存、锁、刷新编排照常由 SDK 负责，仅实际获取委托给 Fetcher；AppID 仍用于缓存/锁 key
+	Fetcher TokenFetcher
+
+	// SecretProvider 动态获取 AppSecret（可选），设置后可不填 AppSecret
+	// 每次从微信获取 token 时调用，AppSecret 轮换后无需重建 Client；返回值变化时会清除负缓存
+	SecretProvider func(ctx context.Context) (string, error)
+
 	// Cache 自定义缓存实现（优先级最高）
 	Cache token.Cache
 
 	// RedisClient Redis 单点客户端指针
 	RedisClient *redis.Client
 
+	// RedisReadClient RedisClient 对应的只读副本（可选），用于读多写少的 token 获取路径以减轻主库压力
+	// 缓存读取走副本；写入、删除、分布式锁（副本无法可靠 SETNX）以及锁内复核始终走 RedisClient，避免复制延迟导致重复刷新
+	// 须同时设置 RedisClient；仅在选用 RedisClient 作为缓存时生效（集群请使用 ClusterOptions.ReadOnly）
+	RedisReadClient *redis.Client
+
 	// RedisClusterClient Redis 集群客户端指针
 	RedisClusterClient *redis.ClusterClient
 
+	// LockRedisClient 专用于分布式锁的 Redis 单点客户端（可选），优先于缓存自带的锁
+	// 用于自定义 Cache（未实现 TokenLocker）+ Redis 锁的组合；Close 不会关闭它。设置后 RedisFastPath 不生效
+	LockRedisClient *redis.Client
+
+	// LockRedisClusterClient 专用于分布式锁的 Redis 集群客户端（可选），用法同 LockRedisClient
+	LockRedisClusterClient *redis.ClusterClient
+
+	// PreferRedis 同时设置 RedisClient 与 RedisClusterClient（或 LockRedisClient 与 LockRedisClusterClient）时选用哪一个：PreferRedisCluster（默认）或 PreferRedisSingle
+	// 迁移 Redis 拓扑期间可两者都保留，通过此字段切换而无需把另一个置 nil；开启 StrictBackend 时两者同时设置仍会报错
+	PreferRedis RedisPreference
+
+	// StrictBackend 是否严格校验缓存来源（默认关闭）
+	// 默认同时设置 Cache、RedisClusterClient、RedisClient 中的多个时按 Cache > RedisClusterClient > RedisClient 选择其一；
+	// 开启后 NewClient 返回 ErrMultipleBackends 并列出冲突字段，避免误以为在用 Redis 实际却用了其他缓存
+	StrictBackend bool
+
 	// DistLockStrategy 分布式锁策略：auto/on/off；默认 auto
 	DistLockStrategy token.DistLockStrategy
 
+	// LockerOptions 内置 Redis 分布式锁的重试退避参数（首次间隔、上限、次数、抖动、首次加锁前的随机延迟）；零值字段使用默认值
+	// 延迟敏感的场景可调小间隔快速重试，配额敏感的场景可放慢节奏；自定义缓存自带的 TokenLocker 不受影响
+	LockerOptions LockerOptions
+
+	// LockWaitTimeout 分布式锁被其他实例持有、重试后仍未取到时，轮询缓存等待对方写入 token 的最长时间
+	// 等到则视为成功返回；默认 0，直接返回 CodeLock。同时受 ctx 截止时间约束
+	LockWaitTimeout time.Duration
+
+	// AllowLocalRefreshOnLockFail 取分布式锁失败时是否退回本地刷新（默认关闭，仅 DistLockStrategy 为 auto 时生效）
+	// auto 下取锁失败（如 Redis 短暂抖动）后总会再读一次缓存，命中有效 token 即返回；开启后仍未命中时不再返回 CodeLock，
+	// 而是只持本地锁向微信获取。代价：多实例同时降级时各自获取，后获取的 token 会使先前的失效（40001 自动重试可兜底）。
+	// DistLockOn 始终严格：取锁失败即返回 CodeLock
+	AllowLocalRefreshOnLockFail bool
+
 	// HTTPTimeout 调用微信接口的超时时间；默认 10s
+	// 作用于单次 HTTP 请求全程（连接、发送、读取响应），与 Transport 的连接池参数相互独立
 	HTTPTimeout time.Duration
+
+	// Transport 自定义 HTTP 连接池，同时用于获取 token 与调用业务接口
+	// 默认基于 http.DefaultTransport 克隆，并调大 MaxIdleConnsPerHost（20）以适配集中访问微信少数 host 的场景
+	// Transport 自身的 ResponseHeaderTimeout 等超时与 HTTPTimeout 同时生效，以先到者为准
+	Transport *http.Transport
+
+	// TLSConfig 出站 HTTPS 的 TLS 配置（可选），同时作用于获取 token 与调用业务接口
+	// 典型用途：开发/预发环境经过做 TLS 解密的企业代理时，将代理 CA 加入 RootCAs
+	// ⚠️ 安全警告：InsecureSkipVerify=true 会关闭证书校验，AppSecret 与 access_token 可被中间人截获，切勿在生产环境使用；
+	// 优先通过 RootCAs 信任企业 CA。设置 Transport 时在其克隆上替换 TLSClientConfig，不修改传入的 Transport
+	TLSConfig *tls.Config
+
+	// MaxRetries HTTP 层最大重试次数（默认 0，不重试）
+	// 仅重试 429/503 响应与 GET 请求的网络错误；响应带 Retry-After 时按其等待，否则指数退避
+	MaxRetries int
+
+	// MaxRetryWait 单次重试等待上限（含 Retry-After），同时受 ctx 截止时间约束；默认 10s
+	MaxRetryWait time.Duration
+
+	// MaxResponseBytes 普通接口（含获取 token）响应体大小上限；默认 10MB，超出返回 CodeResponseTooLarge
+	MaxResponseBytes int64
+
+	// MaxMediaBytes 素材、二维码图片等二进制下载的大小上限；默认 32MB
+	MaxMediaBytes int64
+
+	// DefaultHeaders 附加到每个出站请求的 Header（可选），如出口代理的鉴权 Header、租户标识
+	// 仅补充请求中未设置的项，不覆盖 Content-Type 等单次请求的 Header；若包含 User-Agent，则优先于 UserAgent
+	DefaultHeaders http.Header
+
+	// UserAgent 出站请求的 User-Agent；默认 wxgo/1.0.0
+	UserAgent string
+
+	// RequestIDFunc 从 ctx 提取请求 ID（可选）；返回非空时以 X-Request-Id Header 随每个出站请求发送，便于链路关联
+	RequestIDFunc func(ctx context.Context) string
+
+	// ResponseTap 响应旁路回调（可选，诊断用），每次请求微信（含获取 token、二维码图片下载）读取响应后调用
+	// endpoint 为请求路径（不含 query），非 2xx 响应的 body 为 nil；body 中的 access_token、ticket、session_key 等字段值已替换为 REDACTED
+	// 用于在生产环境捕获异常的响应结构以便反馈；在请求协程中同步调用，需并发安全且尽快返回，不得修改 body
+	ResponseTap func(endpoint string, status int, body []byte)
+
+	// DefaultTimeout 调用方传入的 ctx 没有截止时间（如 context.Background()）时，
+	// 每次操作（含缓存读写、加锁、HTTP 请求）隐式附加的超时；默认 10s
+	// 调用方 ctx 已设置截止时间时不生效，以调用方为准
+	DefaultTimeout time.Duration
+
+	// BaseURL 微信 API 根地址；默认 https://api.weixin.qq.com，可指向代理或测试服务
+	BaseURL string
+
+	// MPBaseURL 公众平台资源（二维码图片 showqrcode）的根地址；默认 https://mp.weixin.qq.com
+	// 与 BaseURL 相互独立，测试时可同时指向本地服务；支持 IP 与端口（如 http://127.0.0.1:8080、http://[::1]:8080）
+	MPBaseURL string
+
+	// KeyPrefix 缓存/锁 key 前缀；默认 wxgo，即 wxgo:token:{appid}
+	// 多个业务共用同一 Redis 时可用于隔离
+	KeyPrefix string
+
+	// RedisFastPath 是否开启 Redis 快速路径（默认关闭）
+	// 缓存未命中时以 Lua 脚本在一次往返内完成「读缓存 + 占用刷新锁」，省去 GET → SETNX → GET 的多次往返与锁重试；
+	// token 存在但进入提前刷新窗口、或锁已被他人持有时，退回常规加锁流程。仅作用于 RedisClient/RedisClusterClient 内置缓存，
+	// 自定义 Cache 不受影响；集群上须同时开启 KeyHashTag，否则 NewClient 报错
+	RedisFastPath bool
+
+	// KeyHashTag 是否以 Redis Cluster hash tag 包裹 key 中的 AppID（默认关闭）
+	// 开启后 key 变为 wxgo:token:{appid} 与 wxgo:token_lock:{appid}，同一 AppID 的缓存与锁落在同一 slot，
+	// 可在其上执行多 key Lua 脚本。会改变 key 布局：切换前后的实例不共享缓存与锁，请整体滚动切换；
+	// KeyPrefix 中不要包含 {，否则 hash tag 取自前缀
+	KeyHashTag bool
+
+	// TTLJitter 缓存 TTL 与提前刷新窗口的随机抖动比例（如 0.1），取值 [0,1)，超出时 NewClient 报错；默认 0 不抖动
+	// 大规模部署中各实例同时拿到同一 token 后会在同一时刻判定过期，开启后各实例错开刷新，降低锁争用峰值
+	TTLJitter float64
+
+	// HotCacheTTL 在进程内保留已解析 token 副本的时长（默认 0 关闭），取值 [0, 1m]，超出时 NewClient 报错
+	// 高 QPS 服务开启（如 1s）后，保留期内的 GetAccessToken 不访问 Redis、不反序列化 JSON。
+	// 代价：其他实例强制刷新 token 后，本实例最多在 HotCacheTTL 内仍返回旧 token；40001 自动刷新重试会立即丢弃副本
+	HotCacheTTL time.Duration
+
+	// EarlyRefresh token 实际过期前多久即视为过期并刷新；nil 使用默认 5 分钟，负数时 NewClient 报错
+	// 设为 0（如 WithEarlyRefresh(0)）表示用到最后一秒：仅在 now 晚于 ExpiresAt 时刷新，可降低共享 AppID 下的刷新频率。
+	// 此时请求发出与微信判定之间的时差可能让临界 token 返回 40001，需保持 AutoRefreshOnInvalidToken 开启（默认）由其刷新重试
+	EarlyRefresh *time.Duration
+
+	// CacheTTLPadding 写入缓存时在 expires_in 之外额外保留的时长（默认 0），取值 [0, 1h]，超出时 NewClient 报错
+	// 只延长缓存 key 的 TTL，不改变 token 的 ExpiresAt，过期判断与刷新时机不变；开启 TTLJitter 时抖动不会缩短 padding，
+	// 缓存至少保留到 ExpiresAt 之后 padding 时长（FileCache 同样按此 TTL 保留）；
+	// 配合 StaleIfError 使用，使刚过期的 token 仍可从缓存读出用于兜底。应远小于 expires_in（通常 7200s），如 1~5 分钟
+	CacheTTLPadding time.Duration
+
+	// StaleIfError 获取 token 因网络错误、5xx、非 JSON 响应或微信系统繁忙（-1）失败时，
+	// 若缓存中仍有已过期的 token，则返回它与 CodeStale（error 为 nil），调用方可据此判断为尽力而为的值
+	// 微信明确返回的业务错误（如 AppSecret 错误）不会兜底；默认关闭
+	// 兜底窗口为 CacheTTLPadding，未设置时为 5 分钟：写入缓存的 TTL 相应延长，Redis 与文件缓存中过期 token 在窗口内仍可读出，更早过期的 token 不会返回
+	StaleIfError bool
+
+	// BackgroundRefresh 是否开启后台刷新（默认关闭）
+	// 开启后 Client 启动一个后台协程，在 token 进入提前刷新窗口时主动刷新，请求路径几乎总是命中缓存；
+	// 失败时记录日志并每 30s 重试。不再使用 Client 时须调用 Close 停止协程，进行中的微信请求会随之取消
+	BackgroundRefresh bool
+
+	// NegativeCache 是否开启负缓存（默认关闭）
+	// 开启后，AppID/AppSecret 错误、IP 不在白名单等不可重试的失败会在 NegativeCacheTTL 内直接返回同一错误，
+	// 避免故障期间大量请求打到微信；网络错误、5xx、限流等可重试错误不会被缓存
+	NegativeCache bool
+
+	// NegativeCacheTTL 负缓存时长；默认 5s
+	NegativeCacheTTL time.Duration
+
+	// Mock 是否开启 Mock 模式（默认关闭），用于本地开发与 CI
+	// 开启后不访问微信：token 接口返回 MockAccessToken，生成二维码返回 MockQRCodeTicket/MockQRCodeURL，
+	// 其余接口返回 errcode=0；只替换 HTTP 层，缓存与锁逻辑照常执行。开启时忽略 Transport
+	Mock bool
+
+	// DedupeQRCode 是否合并并发的相同永久二维码请求（默认关闭）
+	// 永久码对同一场景值是幂等的，开启后同一时刻的相同请求只调用一次微信并共享结果；临时码不受影响
+	DedupeQRCode bool
+
+	// Logger 日志输出（可选），如配置可疑时的告警；*log.Logger 即可
+	Logger Logger
+
+	// Metrics 指标回调（可选），如 metrics/prometheus 子模块提供的 Collector
+	Metrics Metrics
+
+	// Clock 时间源（可选），默认系统时间；测试中可注入可控时钟，无需 time.Sleep 即可验证过期逻辑
+	// 同时实现 TimerClock 时，BackgroundRefresh 的等待也由它计时
+	Clock Clock
+
+	// NonceFunc 签名随机串生成函数（可选），默认 crypto/rand 生成 16 位字母数字
+	// 与 Clock 配合注入固定值后，SignJSConfig 等签名结果确定，便于编写 golden 测试；生产环境请保持默认
+	NonceFunc func() string
+
+	// AutoRefreshOnInvalidToken 接口返回 40001/42001 时是否强制刷新 token 并重试一次；nil 视为开启
+	AutoRefreshOnInvalidToken *bool
 }
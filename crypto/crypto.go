@@ -0,0 +1,204 @@
+// Package crypto 实现微信消息加解密（安全模式），用于公众号/小程序回调
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// encodingAESKeyLength EncodingAESKey 长度（base64 去掉末尾 =）
+	encodingAESKeyLength = 43
+	// pkcs7BlockSize 微信约定的补位块大小
+	pkcs7BlockSize = 32
+	// randomPrefixLength 明文前的随机字节数
+	randomPrefixLength = 16
+)
+
+var (
+	// ErrInvalidAESKey EncodingAESKey 格式不正确
+	ErrInvalidAESKey = errors.New("wxgo/crypto: invalid encoding aes key")
+
+	// ErrInvalidSignature 签名校验失败
+	ErrInvalidSignature = errors.New("wxgo/crypto: invalid signature")
+
+	// ErrDecrypt 密文无法用任何已配置的密钥解开
+	ErrDecrypt = errors.New("wxgo/crypto: decrypt failed")
+
+	// ErrAppIDMismatch 解密后的 AppID 与配置不一致
+	ErrAppIDMismatch = errors.New("wxgo/crypto: appid mismatch")
+)
+
+// MsgCrypto 消息加解密器，并发安全
+// 支持 EncodingAESKey 轮换：Encrypt 始终使用主密钥，Decrypt 先试主密钥，失败再依次尝试旧密钥
+type MsgCrypto struct {
+	token string
+	appID string
+	keys  [][]byte // keys[0] 为主密钥
+}
+
+// NewMsgCrypto 创建消息加解密器
+// aesKeys 第一个为当前（主）EncodingAESKey，其后为轮换期间仍需兼容的旧密钥
+func NewMsgCrypto(token, appID string, aesKeys ...string) (*MsgCrypto, error) {
+	if token == "" {
+		return nil, errors.New("wxgo/crypto: token is required")
+	}
+	if appID == "" {
+		return nil, errors.New("wxgo/crypto: appid is required")
+	}
+	if len(aesKeys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", ErrInvalidAESKey)
+	}
+
+	keys := make([][]byte, 0, len(aesKeys))
+	for i, k := range aesKeys {
+		key, err := decodeAESKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("aes key #%d: %w", i, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return &MsgCrypto{token: token, appID: appID, keys: keys}, nil
+}
+
+// Signature 计算消息签名：sha1(sort(token, timestamp, nonce, encrypted))
+// 明文模式校验 URL 时 encrypted 传空串
+func (m *MsgCrypto) Signature(timestamp, nonce, encrypted string) string {
+	parts := []string{m.token, timestamp, nonce}
+	if encrypted != "" {
+		parts = append(parts, encrypted)
+	}
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySignature 校验消息签名，以常量时间比较，避免按耗时逐字节猜出签名
+func (m *MsgCrypto) VerifySignature(signature, timestamp, nonce, encrypted string) error {
+	if subtle.ConstantTimeCompare([]byte(m.Signature(timestamp, nonce, encrypted)), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Encrypt 使用主密钥加密消息，返回 base64 密文
+func (m *MsgCrypto) Encrypt(msg []byte) (string, error) {
+	prefix := make([]byte, randomPrefixLength)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", fmt.Errorf("wxgo/crypto: generate random prefix: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(prefix)
+	var msgLen [4]byte
+	binary.BigEndian.PutUint32(msgLen[:], uint32(len(msg)))
+	buf.Write(msgLen[:])
+	buf.Write(msg)
+	buf.WriteString(m.appID)
+
+	plain := pkcs7Pad(buf.Bytes())
+	key := m.keys[0]
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("wxgo/crypto: %w", err)
+	}
+	out := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, key[:aes.BlockSize]).CryptBlocks(out, plain)
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt 解密 base64 密文并校验 AppID，返回消息明文
+// 先用主密钥，失败后依次尝试旧密钥，保证轮换期间的回调不失败
+func (m *MsgCrypto) Decrypt(encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: base64: %v", ErrDecrypt, err)
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("%w: ciphertext length %d", ErrDecrypt, len(data))
+	}
+
+	var lastErr error
+	for _, key := range m.keys {
+		msg, err := m.decryptWithKey(key, data)
+		if err == nil {
+			return msg, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (m *MsgCrypto) decryptWithKey(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, key[:aes.BlockSize]).CryptBlocks(plain, data)
+
+	plain, err = pkcs7Unpad(plain)
+	if err != nil {
+		return nil, err
+	}
+	if len(plain) < randomPrefixLength+4 {
+		return nil, fmt.Errorf("%w: plaintext too short", ErrDecrypt)
+	}
+
+	content := plain[randomPrefixLength:]
+	msgLen := int(binary.BigEndian.Uint32(content[:4]))
+	if msgLen < 0 || 4+msgLen > len(content) {
+		return nil, fmt.Errorf("%w: invalid message length", ErrDecrypt)
+	}
+
+	msg := content[4 : 4+msgLen]
+	if string(content[4+msgLen:]) != m.appID {
+		return nil, ErrAppIDMismatch
+	}
+	return msg, nil
+}
+
+func decodeAESKey(k string) ([]byte, error) {
+	if len(k) != encodingAESKeyLength {
+		return nil, fmt.Errorf("%w: length must be %d", ErrInvalidAESKey, encodingAESKeyLength)
+	}
+	key, err := base64.StdEncoding.DecodeString(k + "=")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAESKey, err)
+	}
+	return key, nil
+}
+
+func pkcs7Pad(data []byte) []byte {
+	pad := pkcs7BlockSize - len(data)%pkcs7BlockSize
+	return append(data, bytes.Repeat([]byte{byte(pad)}, pad)...)
+}
+
+func pkcs7Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty plaintext", ErrDecrypt)
+	}
+	pad := int(data[len(data)-1])
+	if pad < 1 || pad > pkcs7BlockSize || pad > len(data) {
+		return nil, fmt.Errorf("%w: invalid padding", ErrDecrypt)
+	}
+	for _, b := range data[len(data)-pad:] {
+		if int(b) != pad {
+			return nil, fmt.Errorf("%w: invalid padding", ErrDecrypt)
+		}
+	}
+	return data[:len(data)-pad], nil
+}
@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	testToken = "callback_token"
+	testAppID = "wx_crypto"
+	oldAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	newAESKey = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789abcdefg"
+	testPlain = "<xml><Content>hello</Content></xml>"
+)
+
+func TestDecryptAfterKeyRotation(t *testing.T) {
+	before, err := NewMsgCrypto(testToken, testAppID, oldAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	cipherText, err := before.Encrypt([]byte(testPlain))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// 轮换后新密钥为主、旧密钥仍保留：旧密钥加密的推送仍可解密
+	rotated, err := NewMsgCrypto(testToken, testAppID, newAESKey, oldAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	plain, err := rotated.Decrypt(cipherText)
+	if err != nil || string(plain) != testPlain {
+		t.Fatalf("Decrypt after rotation = %q, %v", plain, err)
+	}
+
+	// Encrypt 使用主密钥：只持有旧密钥的一方无法解密
+	reply, err := rotated.Encrypt([]byte(testPlain))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := before.Decrypt(reply); err == nil {
+		t.Fatal("reply decrypted with the previous key, want the primary key to be used")
+	}
+
+	// 旧密钥移除后不再接受
+	newOnly, err := NewMsgCrypto(testToken, testAppID, newAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	if _, err := newOnly.Decrypt(cipherText); err == nil {
+		t.Fatal("payload encrypted with a removed key was decrypted")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	mc, err := NewMsgCrypto(testToken, testAppID, oldAESKey)
+	if err != nil {
+		t.Fatalf("NewMsgCrypto: %v", err)
+	}
+	sig := mc.Signature("1700000000", "nonce", "cipher")
+	if err := mc.VerifySignature(sig, "1700000000", "nonce", "cipher"); err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+	for _, bad := range []string{"", sig[:len(sig)-1], sig + "0", "0" + sig[1:]} {
+		if err := mc.VerifySignature(bad, "1700000000", "nonce", "cipher"); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("VerifySignature(%q) = %v, want ErrInvalidSignature", bad, err)
+		}
+	}
+}
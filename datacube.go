@@ -0,0 +1,97 @@
+package wxgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	datacubeUserSummaryPath  = "/datacube/getusersummary"
+	datacubeUserCumulatePath = "/datacube/getusercumulate"
+
+	// datacubeDateLayout 数据统计接口的日期格式
+	datacubeDateLayout = "2006-01-02"
+	// maxUserAnalysisDays 用户分析接口单次查询的最大天数（含首尾）
+	maxUserAnalysisDays = 7
+
+	errCodeInvalidDateRange = 61501
+)
+
+// UserSummary 用户增减数据（按日、按来源）
+type UserSummary struct {
+	// RefDate 数据日期，YYYY-MM-DD
+	RefDate string `json:"ref_date"`
+	// UserSource 用户渠道，如 0 其他、1 公众号搜索、17 名片分享、30 扫描二维码、51 支付后关注
+	UserSource int `json:"user_source"`
+	// NewUser 新增用户数
+	NewUser int `json:"new_user"`
+	// CancelUser 取消关注用户数
+	CancelUser int `json:"cancel_user"`
+}
+
+// UserCumulate 累计用户数据（按日）
+type UserCumulate struct {
+	// RefDate 数据日期，YYYY-MM-DD
+	RefDate string `json:"ref_date"`
+	// CumulateUser 总用户量
+	CumulateUser int `json:"cumulate_user"`
+}
+
+// GetUserSummary 获取用户增减数据（datacube/getusersummary），begin/end 按其自身时区取日期，含首尾最多 7 天
+// 超出跨度或 end 早于 begin 时返回 CodeInvalidParam；微信判定日期范围不合法（61501，如 end 不早于今天）时返回 CodeInvalidDateRange
+func (c *Client) GetUserSummary(ctx context.Context, begin, end time.Time) ([]UserSummary, Code, error) {
+	var apiResp struct {
+		List []UserSummary `json:"list"`
+	}
+	if code, err := c.queryDatacube(ctx, datacubeUserSummaryPath, begin, end, maxUserAnalysisDays, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return apiResp.List, CodeOK, nil
+}
+
+// GetUserCumulate 获取累计用户数据（datacube/getusercumulate），日期范围规则同 GetUserSummary
+func (c *Client) GetUserCumulate(ctx context.Context, begin, end time.Time) ([]UserCumulate, Code, error) {
+	var apiResp struct {
+		List []UserCumulate `json:"list"`
+	}
+	if code, err := c.queryDatacube(ctx, datacubeUserCumulatePath, begin, end, maxUserAnalysisDays, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return apiResp.List, CodeOK, nil
+}
+
+// queryDatacube 校验日期跨度后调用数据统计接口，61501 映射为 CodeInvalidDateRange
+func (c *Client) queryDatacube(ctx context.Context, path string, begin, end time.Time, maxDays int, out any) (Code, error) {
+	if begin.IsZero() || end.IsZero() {
+		return CodeInvalidParam, fmt.Errorf("begin and end dates are required")
+	}
+	days := datacubeDays(begin, end)
+	if days < 1 {
+		return CodeInvalidParam, fmt.Errorf("end date %s is before begin date %s", end.Format(datacubeDateLayout), begin.Format(datacubeDateLayout))
+	}
+	if days > maxDays {
+		return CodeInvalidParam, fmt.Errorf("date range spans %d days, must be <=%d", days, maxDays)
+	}
+
+	body := map[string]any{
+		"begin_date": begin.Format(datacubeDateLayout),
+		"end_date":   end.Format(datacubeDateLayout),
+	}
+	code, err := c.postJSON(ctx, path, body, out)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ErrCode == errCodeInvalidDateRange {
+		code = CodeInvalidDateRange
+	}
+	return code, err
+}
+
+// datacubeDays 返回 begin 到 end 的日历天数（含首尾）；end 早于 begin 时小于 1
+func datacubeDays(begin, end time.Time) int {
+	civil := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(civil(end).Sub(civil(begin)).Hours()/24) + 1
+}
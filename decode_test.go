@@ -0,0 +1,120 @@
+package wxgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+func TestDecodeWeChatResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		// tokenBody/apiBody 分别为 token 接口与普通 API 返回的响应体
+		tokenBody string
+		apiBody   string
+		code      wxgo.Code
+		rid       string
+	}{
+		{
+			name:      "errcode with rid",
+			tokenBody: `{"errcode":40013,"errmsg":"invalid appid","rid":"6650a1b2-1c2d3e4f-5a6b7c8d"}`,
+			apiBody:   `{"errcode":45009,"errmsg":"reach max api daily quota limit rid: 6650a1b2-1c2d3e4f-5a6b7c8d"}`,
+			code:      wxgo.CodeAPIError,
+			rid:       "6650a1b2-1c2d3e4f-5a6b7c8d",
+		},
+		{
+			name:      "html error page",
+			tokenBody: "<html><body>502 Bad Gateway</body></html>",
+			apiBody:   "<html><body>502 Bad Gateway</body></html>",
+			code:      wxgo.CodeNonJSONResponse,
+		},
+		{
+			name:      "truncated json",
+			tokenBody: `{"access_token":"tk","expi`,
+			apiBody:   `{"errcode":0,"value":"v`,
+			code:      wxgo.CodeInvalidResponse,
+		},
+		{
+			name:      "bom before json",
+			tokenBody: "\ufeff" + `{"access_token":"tk","expires_in":7200}`,
+			apiBody:   "\ufeff" + `{"errcode":0,"value":"v"}`,
+			code:      wxgo.CodeOK,
+		},
+		{
+			name:      "success",
+			tokenBody: `{"access_token":"tk","expires_in":7200}`,
+			apiBody:   `{"errcode":0,"errmsg":"ok","value":"v"}`,
+			code:      wxgo.CodeOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokenBody atomic.Value
+			tokenBody.Store(tt.tokenBody)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/cgi-bin/token" {
+					_, _ = w.Write([]byte(tokenBody.Load().(string)))
+					return
+				}
+				_, _ = w.Write([]byte(tt.apiBody))
+			}))
+			defer srv.Close()
+
+			c, err := wxgo.NewClient(wxgo.Config{AppID: "wx_decode", AppSecret: "secret", BaseURL: srv.URL})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer c.Close()
+
+			tk, code, err := c.GetAccessToken(context.Background())
+			checkDecode(t, "token", code, err, tt.code, tt.rid)
+			if tt.code == wxgo.CodeOK && tk != "tk" {
+				t.Fatalf("token = %q, want tk", tk)
+			}
+
+			// API 路径需要可用的 token
+			tokenBody.Store(`{"access_token":"tk","expires_in":7200}`)
+			var out struct {
+				Value string `json:"value"`
+			}
+			code, err = c.APIGet(context.Background(), "/cgi-bin/test", nil, &out)
+			checkDecode(t, "api", code, err, tt.code, tt.rid)
+			if tt.code == wxgo.CodeOK && out.Value != "v" {
+				t.Fatalf("api value = %q, want v", out.Value)
+			}
+		})
+	}
+}
+
+// checkDecode 检查结果码，以及错误是否为对应的 *APIError（含 rid）或哨兵错误
+func checkDecode(t *testing.T, path string, code wxgo.Code, err error, want wxgo.Code, rid string) {
+	t.Helper()
+	if code != want {
+		t.Fatalf("%s: code = %v (err %v), want %v", path, code, err, want)
+	}
+	switch want {
+	case wxgo.CodeOK:
+		if err != nil {
+			t.Fatalf("%s: err = %v", path, err)
+		}
+	case wxgo.CodeAPIError:
+		var apiErr *wxgo.APIError
+		if !errors.As(err, &apiErr) || apiErr.RID != rid {
+			t.Fatalf("%s: err = %v, want *APIError with rid %q", path, err, rid)
+		}
+	case wxgo.CodeNonJSONResponse:
+		if !errors.Is(err, wxgo.ErrNonJSONResponse) {
+			t.Fatalf("%s: err = %v, want ErrNonJSONResponse", path, err)
+		}
+	default:
+		if err == nil {
+			t.Fatalf("%s: err = nil for %v", path, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package wxgo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// defaultEnvPrefix ConfigFromEnv 未指定前缀时使用的环境变量前缀
+const defaultEnvPrefix = "WXGO"
+
+// ConfigFromEnv 从环境变量构建 Config，适用于 12-factor 部署，无需引入配置框架
+// 读取以下变量（prefix 为空时使用 WXGO）：
+//
+//	{PREFIX}_APP_ID        AppID（必填）
+//	{PREFIX}_APP_SECRET    AppSecret；未设置时需另行配置 SecretProvider 或 Fetcher，否则 NewClient 报错
+//	{PREFIX}_REDIS_ADDR    Redis 地址，如 127.0.0.1:6379，或 redis://:password@host:6379/0 形式的 URL；设置后创建 RedisClient
+//	{PREFIX}_DIST_LOCK     分布式锁策略：auto/on/off
+//	{PREFIX}_HTTP_TIMEOUT  HTTP 超时，time.ParseDuration 格式，如 5s
+//
+// 返回的 closeFn 关闭由 {PREFIX}_REDIS_ADDR 创建的 Redis 客户端（未设置时为空操作，始终非 nil）；
+// Client.Close 不会关闭它，调用方应在所有以该 Config 创建的 Client（可有多个，或经 Registry.Add 注册）关闭后调用；
+// 返回 error 时无需调用。返回的 Config 可继续修改其他字段后传给 NewClient
+func ConfigFromEnv(prefix string) (cfg Config, closeFn func() error, err error) {
+	prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "_")
+	if prefix == "" {
+		prefix = defaultEnvPrefix
+	}
+	env := func(name string) (string, string) {
+		key := prefix + "_" + name
+		return key, strings.TrimSpace(os.Getenv(key))
+	}
+
+	key, appID := env("APP_ID")
+	if appID == "" {
+		return Config{}, nil, fmt.Errorf("%w: environment variable %s is not set", ErrMissingAppID, key)
+	}
+	cfg.AppID = appID
+	_, cfg.AppSecret = env("APP_SECRET")
+
+	if key, v := env("DIST_LOCK"); v != "" {
+		strategy := DistLockStrategy(strings.ToLower(v))
+		switch strategy {
+		case DistLockAuto, DistLockOn, DistLockOff:
+			cfg.DistLockStrategy = strategy
+		default:
+			return Config{}, nil, fmt.Errorf("invalid %s %q: must be auto, on or off", key, v)
+		}
+	}
+
+	if key, v := env("HTTP_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout <= 0 {
+			return Config{}, nil, fmt.Errorf("invalid %s %q: must be a positive duration such as 5s", key, v)
+		}
+		cfg.HTTPTimeout = timeout
+	}
+
+	// 最后创建 Redis 客户端，前面的校验失败时无需关闭
+	if key, addr := env("REDIS_ADDR"); addr != "" {
+		opts := &redis.Options{Addr: addr}
+		if strings.Contains(addr, "://") {
+			parsed, err := redis.ParseURL(addr)
+			if err != nil {
+				// 错误中不回显 URL，避免泄露其中的密码
+				return Config{}, nil, fmt.Errorf("invalid %s: %v", key, redactRedisURLError(err, addr))
+			}
+			opts = parsed
+		}
+		cfg.RedisClient = redis.NewClient(opts)
+		return cfg, cfg.RedisClient.Close, nil
+	}
+	return cfg, func() error { return nil }, nil
+}
+
+// redactRedisURLError 去掉解析错误中回显的 Redis URL
+func redactRedisURLError(err error, rawURL string) string {
+	return strings.ReplaceAll(err.Error(), rawURL, "<redacted>")
+}
@@ -0,0 +1,75 @@
+package wxgo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/qingfeng-studio/wxgo"
+)
+
+func TestConfigFromEnvRedisOutlivesClients(t *testing.T) {
+	mr := miniredis.RunT(t)
+	t.Setenv("WXENV_APP_ID", "wx_env")
+	t.Setenv("WXENV_APP_SECRET", "secret")
+	t.Setenv("WXENV_REDIS_ADDR", mr.Addr())
+
+	cfg, closeEnv, err := wxgo.ConfigFromEnv("WXENV")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	cfg.Mock = true
+
+	first, err := wxgo.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	second, err := wxgo.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer second.Close()
+
+	// 关闭第一个 Client 不应关闭共用的 Redis 客户端
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, _, err := second.GetAccessToken(context.Background()); err != nil {
+		t.Fatalf("GetAccessToken after closing another client: %v", err)
+	}
+	if !mr.Exists("wxgo:token:wx_env") {
+		t.Fatalf("token not cached in redis, keys: %v", mr.Keys())
+	}
+
+	if err := closeEnv(); err != nil {
+		t.Fatalf("closeEnv: %v", err)
+	}
+	if err := cfg.RedisClient.Ping(context.Background()).Err(); err == nil {
+		t.Fatal("redis client still usable after closeEnv")
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	t.Setenv("WXENV_APP_ID", "")
+	if _, closeEnv, err := wxgo.ConfigFromEnv("WXENV"); err == nil || closeEnv != nil {
+		t.Fatalf("missing APP_ID: err=%v closeEnv=%v", err, closeEnv != nil)
+	}
+
+	t.Setenv("WXENV_APP_ID", "wx_env")
+	t.Setenv("WXENV_DIST_LOCK", "sometimes")
+	if _, _, err := wxgo.ConfigFromEnv("WXENV"); err == nil {
+		t.Fatal("invalid DIST_LOCK accepted")
+	}
+
+	t.Setenv("WXENV_DIST_LOCK", "ON")
+	cfg, closeEnv, err := wxgo.ConfigFromEnv("WXENV")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	if cfg.DistLockStrategy != wxgo.DistLockOn || cfg.RedisClient != nil {
+		t.Fatalf("cfg = %+v", cfg)
+	}
+	if err := closeEnv(); err != nil {
+		t.Fatalf("closeEnv without redis: %v", err)
+	}
+}
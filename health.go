@@ -0,0 +1,83 @@
+package wxgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qingfeng-studio/wxgo/internal/token"
+)
+
+// HealthComponent 健康检查失败的子系统
+type HealthComponent string
+
+const (
+	// HealthComponentRedis Redis 不可达
+	HealthComponentRedis HealthComponent = "redis"
+	// HealthComponentCache 缓存读写失败
+	HealthComponentCache HealthComponent = "cache"
+	// HealthComponentLock 分布式锁获取失败
+	HealthComponentLock HealthComponent = "lock"
+	// HealthComponentWeChat 微信接口不可达或凭证无效
+	HealthComponentWeChat HealthComponent = "wechat"
+)
+
+// HealthError 健康检查失败的结构化错误
+type HealthError struct {
+	// Component 失败的子系统
+	Component HealthComponent
+	// Code 对应的错误码
+	Code Code
+	// Auth 是否为凭证/配置类错误（如 AppSecret 错误、IP 未加白），重试无意义
+	Auth bool
+	// Err 原始错误
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("wxgo: health check failed (component=%s, code=%s): %v", e.Component, e.Code, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+// HealthCheck 检查 SDK 能否正常工作，适用于 readiness 探针
+// 先 Ping 缓存后端（实现了 Pinger 的缓存），再获取 Access Token；缓存中已有有效 token 时不会请求微信
+// 失败时返回 *HealthError，标明失败的子系统以及是否为凭证错误
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if err := c.PingCache(ctx); err != nil {
+		component := HealthComponentCache
+		if c.cfg.Cache == nil && (c.cfg.RedisClient != nil || c.cfg.RedisClusterClient != nil) {
+			component = HealthComponentRedis
+		}
+		return &HealthError{Component: component, Code: CodeCacheGet, Err: err}
+	}
+
+	_, code, err := c.token.GetAccessToken(ctx)
+	if err == nil {
+		return nil
+	}
+
+	herr := &HealthError{Component: HealthComponentWeChat, Code: code, Err: err}
+	switch code {
+	case CodeCacheGet, CodeCacheSet:
+		herr.Component = HealthComponentCache
+	case CodeLock:
+		herr.Component = HealthComponentLock
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && token.IsCredentialErrCode(apiErr.ErrCode) {
+		herr.Auth = true
+	}
+	return herr
+}
+
+// PingCache 探测缓存后端（内存/Redis/集群）是否可达
+// 自定义缓存未实现 Pinger 接口时直接返回 nil
+func (c *Client) PingCache(ctx context.Context) error {
+	return c.token.PingCache(ctx)
+}
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+// BackendInfo Manager 实际选用的缓存与分布式锁后端，便于排查配置优先级导致的意外选择
+type BackendInfo struct {
+	// Cache 缓存类型：memory / redis / redis-cluster / custom
+	Cache string
+	// DistLock 是否启用了分布式锁
+	DistLock bool
+	// Locker 分布式锁类型：redis / redis-cluster（内置 Redis 锁）、custom（缓存自带的 TokenLocker）；未启用时为空
+	Locker string
+	// LockStrategy 生效的分布式锁策略（auto/on/off）
+	LockStrategy DistLockStrategy
+	// RedisFastPath 是否启用了 Redis 快速路径
+	RedisFastPath bool
+}
+
+// Backend 返回实际选用的缓存与锁后端
+func (m *Manager) Backend() BackendInfo {
+	info := BackendInfo{
+		Cache:         string(m.selectedKind),
+		DistLock:      m.distLocker != nil,
+		LockStrategy:  m.lockStrategy,
+		RedisFastPath: m.claimer != nil,
+	}
+	switch l := m.distLocker.(type) {
+	case nil:
+	case *RedisLocker:
+		info.Locker = string(cacheKindRedis)
+		if _, ok := l.client.(*redis.ClusterClient); ok {
+			info.Locker = string(cacheKindRC)
+		}
+	default:
+		info.Locker = string(cacheKindCustom)
+	}
+	return info
+}
+
+// EffectiveConfig 应用默认值后实际生效的配置，便于启动时输出日志、核对配置优先级与默认值
+// AppSecret 已脱敏；HTTP 相关字段由上层 Client 填充
+type EffectiveConfig struct {
+	AppID string
+	// AppSecret 脱敏后的 AppSecret：仅保留末 4 位；使用 SecretProvider/Fetcher 时为说明文字
+	AppSecret string
+	Provider  Provider
+	// BaseURL 微信 API 根地址；MPBaseURL 为 mp.weixin.qq.com 根地址（二维码图片等）
+	BaseURL   string
+	MPBaseURL string
+
+	// KeyPrefix 生效的 key 前缀；CacheKey/LockKey 为本应用实际使用的缓存与锁 key
+	KeyPrefix string
+	CacheKey  string
+	LockKey   string
+
+	// Backend 选用的缓存与锁后端（含生效的锁策略）
+	Backend BackendInfo
+	// LockTTL 分布式锁租约时间
+	LockTTL time.Duration
+	// LockerOptions 内置 Redis 锁填充默认值后的重试参数；锁来自自定义缓存时不生效
+	LockerOptions               LockerOptions
+	LockWaitTimeout             time.Duration
+	AllowLocalRefreshOnLockFail bool
+
+	// EarlyRefresh 本实例的提前刷新窗口（已含 TTLJitter 抖动）
+	EarlyRefresh time.Duration
+	TTLJitter    float64
+	HotCacheTTL  time.Duration
+	// CacheTTLPadding 过期 token 在缓存中额外保留的时长；未设置但开启 StaleIfError 时为默认的 5 分钟
+	CacheTTLPadding time.Duration
+	// NegativeCacheTTL 负缓存时长；未开启负缓存时为 0
+	NegativeCacheTTL  time.Duration
+	StaleIfError      bool
+	BackgroundRefresh bool
+
+	// DefaultTimeout ctx 无截止时间时单次操作的超时
+	DefaultTimeout time.Duration
+	// HTTPTimeout 单次 HTTP 请求超时；MaxRetries/MaxRetryWait 为 HTTP 层重试次数与单次等待上限
+	HTTPTimeout  time.Duration
+	MaxRetries   int
+	MaxRetryWait time.Duration
+	// AutoRefreshOnInvalidToken 接口返回 40001/42001 时是否强制刷新并重试
+	AutoRefreshOnInvalidToken bool
+}
+
+// EffectiveConfig 返回 Manager 实际生效的配置；HTTP 相关字段为获取 token 使用的客户端的值
+func (m *Manager) EffectiveConfig() EffectiveConfig {
+	c := m.config
+	defaultTimeout := c.DefaultTimeout
+	if defaultTimeout <= 0 {
+		defaultTimeout = transport.DefaultTimeout
+	}
+	retry := m.httpClient.Retry()
+	// RotateSecret 可能同时替换 AppSecret
+	m.secretMu.RLock()
+	secret := c.redactedSecret()
+	m.secretMu.RUnlock()
+	return EffectiveConfig{
+		AppID:                       c.AppID,
+		AppSecret:                   secret,
+		Provider:                    c.provider(),
+		BaseURL:                     c.BaseURL,
+		KeyPrefix:                   effectiveKeyPrefix(c.KeyPrefix),
+		CacheKey:                    m.getCacheKey(),
+		LockKey:                     m.getLockKey(),
+		Backend:                     m.Backend(),
+		LockTTL:                     m.lockTTL,
+		LockerOptions:               c.LockerOptions.withDefaults(),
+		LockWaitTimeout:             c.LockWaitTimeout,
+		AllowLocalRefreshOnLockFail: c.AllowLocalRefreshOnLockFail,
+		EarlyRefresh:                m.earlyRefresh,
+		TTLJitter:                   c.TTLJitter,
+		HotCacheTTL:                 c.HotCacheTTL,
+		CacheTTLPadding:             c.staleWindow(),
+		NegativeCacheTTL:            c.negativeCacheTTL(),
+		StaleIfError:                c.StaleIfError,
+		BackgroundRefresh:           c.BackgroundRefresh,
+		DefaultTimeout:              defaultTimeout,
+		HTTPTimeout:                 m.httpClient.Timeout(),
+		MaxRetries:                  retry.MaxRetries,
+		MaxRetryWait:                retry.MaxWait,
+	}
+}
+
+// redactedSecret 返回可写入日志的 AppSecret：仅保留末 4 位，足以核对轮换是否生效
+func (c *Config) redactedSecret() string {
+	switch {
+	case c.Fetcher != nil && c.AppSecret == "":
+		return "(custom fetcher)"
+	case c.SecretProvider != nil:
+		return "(secret provider)"
+	case len(c.AppSecret) <= 4:
+		return "****"
+	}
+	return "****" + c.AppSecret[len(c.AppSecret)-4:]
+}
@@ -0,0 +1,95 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestEffectiveConfigConcurrentWithRotateSecret(t *testing.T) {
+	m, err := newTestManager(nil, &stubFetcher{})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if _, err := m.RotateSecret(context.Background(), fmt.Sprintf("secret-%04d", i)); err != nil {
+				t.Errorf("RotateSecret: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = m.EffectiveConfig()
+		}
+	}()
+	wg.Wait()
+
+	if got := m.EffectiveConfig().AppSecret; got != "****0049" {
+		t.Fatalf("AppSecret = %q, want ****0049", got)
+	}
+}
+
+// plainCache 不实现 TokenLocker 的自定义缓存
+type plainCache struct {
+	Cache
+}
+
+func TestLockBackendMissingErrorNamesBackends(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "memory cache",
+			cfg:  Config{},
+			want: []string{
+				"selected cache (memory) provides no lock",
+				"checked LockRedisClient/LockRedisClusterClient: not set",
+				"Cache: not set",
+				"RedisClusterClient: not set",
+				"RedisClient: not set",
+				"set LockRedisClient or LockRedisClusterClient (works with any Cache)",
+			},
+		},
+		{
+			name: "custom cache overrides redis",
+			cfg:  Config{Cache: plainCache{NewMemoryCache()}, RedisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})},
+			want: []string{
+				"selected cache (custom) provides no lock",
+				"Cache: token.plainCache is set but does not implement TokenLocker",
+				"RedisClient: set but unused, Cache takes priority",
+				"RedisClusterClient: not set",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.AppID, cfg.AppSecret = "wx_test", "secret"
+			cfg.DistLockStrategy = DistLockOn
+			_, err := NewManager(&cfg)
+			if !errors.Is(err, ErrLockBackendMissing) {
+				t.Fatalf("NewManager = %v, want ErrLockBackendMissing", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// newBenchManager 创建连接 mr 的 Manager，模拟共享同一 Redis 的一个实例
+func newBenchManager(b *testing.B, mr *miniredis.Miniredis, cfg Config) *Manager {
+	b.Helper()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), PoolSize: 64})
+	b.Cleanup(func() { client.Close() })
+	cfg.RedisClient = client
+	m, err := newTestManager(&cfg, &stubFetcher{})
+	if err != nil {
+		b.Fatalf("NewManager: %v", err)
+	}
+	b.Cleanup(func() { m.Close() })
+	return m
+}
+
+// BenchmarkGetToken 对比 RedisFastPath 开启/关闭时的缓存命中，以及多实例并发未命中：
+// 每轮删除共享 token 后 8 个实例同时获取，只有一个实例向微信获取，其余等待或读到新 token
+// redis-cmds/op 为每次操作发往 Redis 的命令数；未命中的耗时主要是等待方的轮询间隔，两种模式相同
+func BenchmarkGetToken(b *testing.B) {
+	const instances = 8
+	ctx := context.Background()
+	for _, mode := range []struct {
+		name     string
+		fastPath bool
+	}{{"lock", false}, {"fastpath", true}} {
+		b.Run(mode.name+"/hit", func(b *testing.B) {
+			mr := miniredis.RunT(b)
+			m := newBenchManager(b, mr, Config{RedisFastPath: mode.fastPath})
+			if _, err := m.GetToken(ctx); err != nil {
+				b.Fatalf("GetToken: %v", err)
+			}
+			start := mr.CommandCount()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := m.GetToken(ctx); err != nil {
+						b.Errorf("GetToken: %v", err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(mr.CommandCount()-start)/float64(b.N), "redis-cmds/op")
+		})
+
+		b.Run(mode.name+"/concurrent-miss", func(b *testing.B) {
+			mr := miniredis.RunT(b)
+			managers := make([]*Manager, instances)
+			for i := range managers {
+				managers[i] = newBenchManager(b, mr, Config{RedisFastPath: mode.fastPath})
+			}
+			key := managers[0].getCacheKey()
+			start := mr.CommandCount()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mr.Del(key)
+				var wg sync.WaitGroup
+				wg.Add(instances)
+				for _, m := range managers {
+					go func(m *Manager) {
+						defer wg.Done()
+						if _, err := m.GetToken(ctx); err != nil {
+							b.Errorf("GetToken: %v", err)
+						}
+					}(m)
+				}
+				wg.Wait()
+			}
+			b.ReportMetric(float64(mr.CommandCount()-start)/float64(b.N), "redis-cmds/op")
+		})
+	}
+}
+
+// BenchmarkGetTokenHotCache 对比缓存命中时 HotCacheTTL 关闭/开启的耗时、内存分配与 Redis 往返
+// 关闭时每次命中都读取 Redis 并反序列化 TokenInfo，开启后保留期内直接返回进程内副本
+func BenchmarkGetTokenHotCache(b *testing.B) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name string
+		ttl  time.Duration
+	}{{"off", 0}, {"on", time.Second}} {
+		b.Run(tt.name, func(b *testing.B) {
+			mr := miniredis.RunT(b)
+			m := newBenchManager(b, mr, Config{HotCacheTTL: tt.ttl})
+			if _, err := m.GetToken(ctx); err != nil {
+				b.Fatalf("GetToken: %v", err)
+			}
+			start := mr.CommandCount()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := m.GetToken(ctx); err != nil {
+					b.Fatalf("GetToken: %v", err)
+				}
+			}
+			b.ReportMetric(float64(mr.CommandCount()-start)/float64(b.N), "redis-cmds/op")
+		})
+	}
+}
@@ -2,6 +2,7 @@ package token
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,6 +19,23 @@ type Cache interface {
 	Delete(ctx context.Context, key string) error
 }
 
+// Pinger 可选接口：缓存若实现它，可用于探测后端是否可达
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Flusher 可选接口：缓存若实现它，可按 key 前缀批量删除（如清空某个 KeyPrefix 下所有 AppID 的 token）
+// 实现只应删除匹配前缀的 key，不得清空整个后端（如 Redis FLUSHDB）
+type Flusher interface {
+	Flush(ctx context.Context, prefix string) error
+}
+
+// primaryReader 读写分离的缓存实现它：锁内复核、强制刷新等需要读到最新写入的场景绕过只读副本，
+// 避免复制延迟使已由其他实例刷新的 token 被误判为未命中而重复刷新
+type primaryReader interface {
+	getPrimary(ctx context.Context, key string) (*TokenInfo, error)
+}
+
 // MemoryCache 内存缓存实现
 type MemoryCache struct {
 	mu    sync.RWMutex
@@ -61,3 +79,28 @@ func (m *MemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Reset 清空全部 Token，适用于测试或管理端"清空所有 token"
+func (m *MemoryCache) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store = make(map[string]*TokenInfo)
+}
+
+// Flush 删除 key 以 prefix 开头的 Token
+func (m *MemoryCache) Flush(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.store {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.store, key)
+		}
+	}
+	return nil
+}
+
+// Ping 内存缓存始终可用
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
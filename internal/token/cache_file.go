@@ -0,0 +1,222 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileCacheMode 缓存文件权限：token 属于敏感信息，仅当前用户可读写
+const fileCacheMode = 0o600
+
+// FileCache 文件缓存实现，适用于无 Redis 的单机部署，进程重启后仍可复用未过期的 token
+// 所有 key 存于同一个 JSON 文件；写入先落临时文件再原子 rename，多进程并发写不会产生半截文件；
+// 读-改-写期间持有 {path}.lock 文件锁（Unix 下为 flock），多进程并发写入不同 key 不会互相覆盖
+// 条目按 Set 传入的 ttl 保留（与 Redis 一致），过期判断由 Manager 依据 ExpiresAt 进行；文件不存在或内容损坏时视为未命中
+type FileCache struct {
+	mu    sync.Mutex
+	path  string
+	clock Clock
+}
+
+// NewFileCache 创建文件缓存实例，path 所在目录需已存在
+// 用作 Manager 的缓存时，ttl 计时使用 Config.Clock
+func NewFileCache(path string) *FileCache {
+	return &FileCache{path: path, clock: realClock{}}
+}
+
+// setClock 由 Manager 注入时间源
+func (f *FileCache) setClock(c Clock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clock = c
+}
+
+// lock 持有进程内互斥锁与跨进程文件锁，返回解锁函数；用于读-改-写
+func (f *FileCache) lock() (func(), error) {
+	f.mu.Lock()
+	lf, err := os.OpenFile(f.path+".lock", os.O_CREATE|os.O_RDWR, fileCacheMode)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("open cache lock file: %w", err)
+	}
+	if err := lockFile(lf); err != nil {
+		lf.Close()
+		f.mu.Unlock()
+		return nil, fmt.Errorf("lock cache file: %w", err)
+	}
+	return func() {
+		_ = unlockFile(lf)
+		lf.Close()
+		f.mu.Unlock()
+	}, nil
+}
+
+// fileEntry 文件中的单个条目；EvictAt 为按写入 ttl 计算的淘汰时间，旧版本写入的条目没有该字段，以 ExpiresAt 为准
+type fileEntry struct {
+	TokenInfo
+	EvictAt time.Time `json:"evict_at"`
+}
+
+// evictAt 返回条目的淘汰时间
+func (e *fileEntry) evictAt() time.Time {
+	if e.EvictAt.IsZero() {
+		return e.ExpiresAt
+	}
+	return e.EvictAt
+}
+
+// Get 从文件获取 Token；已过 ttl 的条目视为未命中，ExpiresAt 已过但仍在 ttl 内（如 CacheTTLPadding）的条目照常返回
+func (f *FileCache) Get(ctx context.Context, key string) (*TokenInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := entries[key]
+	if !ok {
+		return nil, nil
+	}
+	token := entry.TokenInfo
+	return &token, nil
+}
+
+// Set 设置 Token 到文件，条目保留 ttl 时长
+func (f *FileCache) Set(ctx context.Context, key string, token *TokenInfo, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTTL, ttl)
+	}
+
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	entries[key] = &fileEntry{TokenInfo: *token, EvictAt: f.clock.Now().Add(ttl)}
+	return f.save(entries)
+}
+
+// Delete 删除 Token
+func (f *FileCache) Delete(ctx context.Context, key string) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	return f.save(entries)
+}
+
+// Flush 删除 key 以 prefix 开头的 Token
+func (f *FileCache) Flush(ctx context.Context, prefix string) error {
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+	n := len(entries)
+	for key := range entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(entries, key)
+		}
+	}
+	if len(entries) == n {
+		return nil
+	}
+	return f.save(entries)
+}
+
+// Ping 检查缓存文件所在目录是否存在
+func (f *FileCache) Ping(ctx context.Context) error {
+	dir := filepath.Dir(f.path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// load 读取全部条目；文件不存在或无法解析时返回空表，并顺带清理已过 ttl 的条目
+func (f *FileCache) load() (map[string]*fileEntry, error) {
+	entries := make(map[string]*fileEntry)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache file: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]*fileEntry), nil
+	}
+
+	now := f.clock.Now()
+	for k, e := range entries {
+		if e == nil || now.After(e.evictAt()) {
+			delete(entries, k)
+		}
+	}
+	return entries, nil
+}
+
+// save 写入临时文件后原子替换目标文件
+func (f *FileCache) save(entries map[string]*fileEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("create temp cache file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // rename 成功后为空操作
+
+	if err := tmp.Chmod(fileCacheMode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod temp cache file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp cache file: %w", err)
+	}
+	if err := os.Rename(tmpName, f.path); err != nil {
+		return fmt.Errorf("rename cache file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileCacheHonoursTTLBeyondExpiresAt(t *testing.T) {
+	ctx := context.Background()
+	f := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+
+	// 已过 ExpiresAt 但仍在 ttl（padding）内：应可读出，供 StaleIfError 兜底
+	expired := &TokenInfo{AccessToken: "old", ExpiresIn: 7200, ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := f.Set(ctx, "k", expired, time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := f.Get(ctx, "k")
+	if err != nil || got == nil || got.AccessToken != "old" {
+		t.Fatalf("Get = %+v, %v; want the padded entry", got, err)
+	}
+
+	// ttl 已过：视为未命中
+	if err := f.Set(ctx, "short", &TokenInfo{AccessToken: "x", ExpiresAt: time.Now().Add(time.Hour)}, time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if got, err := f.Get(ctx, "short"); err != nil || got != nil {
+		t.Fatalf("Get after ttl = %+v, %v; want miss", got, err)
+	}
+
+	if err := f.Set(ctx, "k", expired, 0); err == nil {
+		t.Fatal("Set with zero ttl succeeded, want ErrInvalidTTL")
+	}
+}
+
+func TestFileCacheUsesManagerClock(t *testing.T) {
+	clock := newFakeClock()
+	f := NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+	m, err := newTestManager(&Config{Cache: f, Clock: clock}, &stubFetcher{})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	if err := f.Set(ctx, "k", &TokenInfo{AccessToken: "a", ExpiresAt: clock.Now().Add(time.Hour)}, time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, _ := f.Get(ctx, "k"); got == nil {
+		t.Fatal("Get before ttl = nil, want hit")
+	}
+	clock.Advance(2 * time.Hour)
+	if got, _ := f.Get(ctx, "k"); got != nil {
+		t.Fatalf("Get after fake clock passed ttl = %+v, want miss", got)
+	}
+}
+
+func TestFileCacheConcurrentWritersDoNotLoseKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	// 两个实例各自持有进程内锁，相当于两个进程共用同一文件
+	caches := []*FileCache{NewFileCache(path), NewFileCache(path)}
+
+	ctx := context.Background()
+	const perCache = 30
+	var wg sync.WaitGroup
+	for i, c := range caches {
+		for j := 0; j < perCache; j++ {
+			wg.Add(1)
+			go func(c *FileCache, key string) {
+				defer wg.Done()
+				if err := c.Set(ctx, key, &TokenInfo{AccessToken: key, ExpiresAt: time.Now().Add(time.Hour)}, time.Hour); err != nil {
+					t.Errorf("Set %s: %v", key, err)
+				}
+			}(c, fmt.Sprintf("k%d-%d", i, j))
+		}
+	}
+	wg.Wait()
+
+	for i := range caches {
+		for j := 0; j < perCache; j++ {
+			key := fmt.Sprintf("k%d-%d", i, j)
+			if got, err := caches[0].Get(ctx, key); err != nil || got == nil {
+				t.Errorf("key %s lost: %+v, %v", key, got, err)
+			}
+		}
+	}
+}
@@ -3,6 +3,8 @@ package token
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -11,18 +13,39 @@ import (
 // RedisCache Redis 缓存实现（单点）
 type RedisCache struct {
 	client *redis.Client
+	reader *redis.Client // Get 使用的只读副本；未配置时与 client 相同
 }
 
 // NewRedisCache 创建 Redis 缓存实例
 func NewRedisCache(client *redis.Client) *RedisCache {
+	return NewRedisCacheWithReader(client, nil)
+}
+
+// NewRedisCacheWithReader 创建读写分离的 Redis 缓存实例：Get 走 reader（如只读副本），Set/Delete/Flush 走 client（主库）
+// reader 为 nil 时读写均使用 client
+func NewRedisCacheWithReader(client, reader *redis.Client) *RedisCache {
+	if reader == nil {
+		reader = client
+	}
 	return &RedisCache{
 		client: client,
+		reader: reader,
 	}
 }
 
-// Get 从 Redis 获取 Token
+// Get 从 Redis 获取 Token；配置了只读副本时从副本读取
 func (r *RedisCache) Get(ctx context.Context, key string) (*TokenInfo, error) {
-	val, err := r.client.Get(ctx, key).Result()
+	return r.get(ctx, r.reader, key)
+}
+
+// getPrimary 从主库读取，不受副本复制延迟影响
+func (r *RedisCache) getPrimary(ctx context.Context, key string) (*TokenInfo, error) {
+	return r.get(ctx, r.client, key)
+}
+
+// get 以指定客户端读取 Token
+func (r *RedisCache) get(ctx context.Context, client *redis.Client, key string) (*TokenInfo, error) {
+	val, err := client.Get(ctx, key).Result()
 	if err == redis.Nil {
 		return nil, nil
 	}
@@ -40,6 +63,11 @@ func (r *RedisCache) Get(ctx context.Context, key string) (*TokenInfo, error) {
 
 // Set 设置 Token 到 Redis
 func (r *RedisCache) Set(ctx context.Context, key string, token *TokenInfo, ttl time.Duration) error {
+	// go-redis 中 ttl<=0 表示不过期，token 会永久残留，直接拒绝
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTTL, ttl)
+	}
+
 	data, err := json.Marshal(token)
 	if err != nil {
 		return err
@@ -53,6 +81,16 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// Flush 以 SCAN 遍历并删除 key 以 prefix 开头的 Token，不使用 FLUSHDB/KEYS
+func (r *RedisCache) Flush(ctx context.Context, prefix string) error {
+	return scanDelete(ctx, r.client, prefix)
+}
+
+// Ping 检查 Redis 是否可达
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // RedisClusterCache Redis 集群缓存实现
 type RedisClusterCache struct {
 	client *redis.ClusterClient
@@ -85,6 +123,11 @@ func (r *RedisClusterCache) Get(ctx context.Context, key string) (*TokenInfo, er
 
 // Set 设置 Token 到 Redis 集群
 func (r *RedisClusterCache) Set(ctx context.Context, key string, token *TokenInfo, ttl time.Duration) error {
+	// go-redis 中 ttl<=0 表示不过期，token 会永久残留，直接拒绝
+	if ttl <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTTL, ttl)
+	}
+
 	data, err := json.Marshal(token)
 	if err != nil {
 		return err
@@ -98,3 +141,67 @@ func (r *RedisClusterCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// Flush 在每个主节点上以 SCAN 遍历并删除 key 以 prefix 开头的 Token
+func (r *RedisClusterCache) Flush(ctx context.Context, prefix string) error {
+	return r.client.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return scanDelete(ctx, node, prefix)
+	})
+}
+
+// Ping 检查 Redis 集群是否可达
+func (r *RedisClusterCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// flushScanCount 每轮 SCAN 的 COUNT 提示，同时作为每批删除的 key 数上限
+const flushScanCount = 100
+
+// scanDelete 在单个节点上 SCAN 匹配前缀的 key 并分批删除，每轮之间检查 ctx，可随时取消
+// 不使用 KEYS（大库上会阻塞 Redis）；集群节点上不同 key 可能属于不同 slot，多 key 的 DEL 会报 CROSSSLOT，
+// 因此每批用 pipeline 逐个 DEL
+func scanDelete(ctx context.Context, client *redis.Client, prefix string) error {
+	match := escapeGlob(prefix) + "*"
+	var cursor uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		keys, next, err := client.Scan(ctx, cursor, match, flushScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if err := deleteKeys(ctx, client, keys); err != nil {
+			return err
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// deleteKeys 以 pipeline 删除一批 key
+func deleteKeys(ctx context.Context, client *redis.Client, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	pipe := client.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// escapeGlob 转义 SCAN MATCH 中的通配字符，使前缀按字面匹配
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestCancelWhileAnotherInstanceHoldsLock(t *testing.T) {
+	mr := miniredis.RunT(t)
+	newInstance := func(f TokenFetcher) *Manager {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		m, err := newTestManager(&Config{RedisClient: client}, f)
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		t.Cleanup(func() { m.Close() })
+		return m
+	}
+	holder := &gatedFetcher{entered: make(chan struct{}), release: make(chan struct{})}
+	defer close(holder.release)
+	waiterFetcher := &stubFetcher{}
+	a, b := newInstance(holder), newInstance(waiterFetcher)
+
+	// a 持有分布式锁并卡在获取 token 上
+	go func() { _, _ = a.GetToken(context.Background()) }()
+	<-holder.entered
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	result, err := b.GetToken(ctx)
+	if !errors.Is(err, context.Canceled) || result.Code != CodeCanceled {
+		t.Fatalf("GetToken = %v, %v, want CodeCanceled", result.Code, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("GetToken returned after %s, want promptly after cancel", elapsed)
+	}
+	if got := waiterFetcher.calls.Load(); got != 0 {
+		t.Fatalf("cancelled caller fetched %d times, want 0", got)
+	}
+}
+
+func TestCancelWhileWaitingForLocalMutex(t *testing.T) {
+	holder := &gatedFetcher{entered: make(chan struct{}), release: make(chan struct{})}
+	m, err := newTestManager(nil, holder)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = m.ForceRefresh(context.Background(), "")
+	}()
+	<-holder.entered
+
+	// 第二个 ForceRefresh 在等待 m.mu 期间被取消：拿到 mutex 后直接返回，不再请求微信
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	codeCh := make(chan Code, 1)
+	go func() {
+		_, code, err := m.ForceRefresh(ctx, "")
+		codeCh <- code
+		errCh <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(holder.release)
+	<-done
+
+	if code, err := <-codeCh, <-errCh; code != CodeCanceled || !errors.Is(err, context.Canceled) {
+		t.Fatalf("ForceRefresh = %v, %v, want CodeCanceled", code, err)
+	}
+	if got := holder.calls.Load(); got != 1 {
+		t.Fatalf("fetch calls = %d, want 1", got)
+	}
+}
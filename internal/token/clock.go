@@ -0,0 +1,63 @@
+package token
+
+import "time"
+
+// Clock 时间源；测试中可注入可控时钟以确定性地验证过期逻辑
+type Clock interface {
+	Now() time.Time
+}
+
+// Timer 由 TimerClock 创建的计时器
+type Timer interface {
+	// C 到期时收到当前时间
+	C() <-chan time.Time
+	// Stop 停止计时器，语义同 time.Timer.Stop
+	Stop() bool
+}
+
+// TimerClock 可选接口：Clock 若实现它，后台刷新的等待也由其计时，测试中推进时钟即可确定性地触发刷新
+type TimerClock interface {
+	Clock
+	NewTimer(d time.Duration) Timer
+}
+
+// realTimer 以 time.Timer 实现 Timer
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time {
+	return r.t.C
+}
+
+func (r realTimer) Stop() bool {
+	return r.t.Stop()
+}
+
+// newTimer 创建 d 后到期的计时器：Clock 实现 TimerClock 时由其创建，否则使用系统计时器
+func newTimer(c Clock, d time.Duration) Timer {
+	if tc, ok := c.(TimerClock); ok {
+		return tc.NewTimer(d)
+	}
+	return realTimer{t: time.NewTimer(d)}
+}
+
+// clockSetter 自行计时的缓存实现它（如 FileCache），由 Manager 注入 Config.Clock
+type clockSetter interface {
+	setClock(Clock)
+}
+
+// realClock 默认时钟，使用系统时间
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// clock 返回有效的时钟
+func (c *Config) clock() Clock {
+	if c.Clock == nil {
+		return realClock{}
+	}
+	return c.Clock
+}
@@ -1,6 +1,18 @@
 package token
 
-import "github.com/go-redis/redis/v8"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	randv2 "math/rand/v2"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
 
 // Config Token 管理器配置
 type Config struct {
@@ -10,30 +22,262 @@ type Config struct {
 	// AppSecret 微信公众号/小程序的 AppSecret
 	AppSecret string
 
+	// Provider token 接口提供方；默认 ProviderMP（公众号/小程序）
+	Provider Provider
+
+	// CorpID 企业微信 corpid（Provider 为 ProviderWorkWeChat 时使用，AppID 为空时取此值）
+	CorpID string
+
+	// CorpSecret 企业微信应用 secret（Provider 为 ProviderWorkWeChat 时使用，AppSecret 为空时取此值）
+	CorpSecret string
+
+	// AgentID 企业微信应用的 agentid（Provider 为 ProviderWorkWeChat 时使用），与 corpid 一起区分缓存/锁 key
+	// 未设置时以 secret 指纹区分；使用 SecretProvider 时必填
+	AgentID string
+
+	// ComponentVerifyTicket 返回最新的 component_verify_ticket（Provider 为 ProviderComponent 时必填）
+	ComponentVerifyTicket func(ctx context.Context) (string, error)
+
+	// StableForceRefreshInterval ProviderStable 下两次 force_refresh 的最小间隔，记录在共享缓存中跨实例生效；默认 72 分钟（每天不超过 20 次）
+	// 间隔内的 ForceRefresh 退回普通获取，返回 CodeForceRefreshThrottled
+	StableForceRefreshInterval time.Duration
+
+	// Credential 管理的凭据名，用于缓存/锁 key（{prefix}:{Credential}:{appID}）；默认 token，第三方平台为 component_token
+	// 同一 AppID 的 jsapi_ticket 等凭据各用一个 Manager，共享缓存、锁、提前刷新与后台刷新逻辑，获取由 Fetcher 完成
+	Credential string
+
+	// Fetcher 自定义 token 获取实现（可选）；设置后不再请求微信，AppSecret 可不填
+	Fetcher TokenFetcher
+
+	// appScope 同一 AppID 下区分应用的 key 后缀：企业微信为 agentid 或 secret 指纹，由 Validate 设置
+	appScope string
+
+	// SecretProvider 动态获取 AppSecret（可选），设置后每次从微信获取 token 时调用，优先于 AppSecret
+	SecretProvider func(ctx context.Context) (string, error)
+
 	// Cache 自定义缓存实现（优先级最高）
 	Cache Cache
 
 	// RedisClient Redis 单点客户端指针
 	RedisClient *redis.Client
 
+	// RedisReadClient RedisClient 的只读副本（可选）：缓存读取走副本，写入、删除与分布式锁始终走 RedisClient
+	RedisReadClient *redis.Client
+
 	// RedisClusterClient Redis 集群客户端指针
 	RedisClusterClient *redis.ClusterClient
 
+	// LockRedisClient 专用于分布式锁的 Redis 单点客户端（可选），与缓存来源无关，如自定义 Cache + Redis 锁
+	LockRedisClient *redis.Client
+
+	// LockRedisClusterClient 专用于分布式锁的 Redis 集群客户端（可选）
+	LockRedisClusterClient *redis.ClusterClient
+
+	// PreferRedis 同时配置单点与集群客户端时选用哪一个：cluster/single；默认 cluster。对缓存与专用锁客户端分别生效
+	PreferRedis RedisPreference
+
+	// StrictBackend 同时配置多个缓存来源（Cache/RedisClusterClient/RedisClient）时报错，而非按优先级选择其一
+	StrictBackend bool
+
 	// DistLockStrategy 分布式锁策略：auto/on/off；默认 auto
 	DistLockStrategy DistLockStrategy
+
+	// LockerOptions 内置 Redis 锁的重试退避参数；零值使用默认值
+	LockerOptions LockerOptions
+
+	// AllowLocalRefreshOnLockFail auto 策略下取分布式锁失败且缓存仍未命中时，只在本地锁内刷新而非返回 CodeLock
+	AllowLocalRefreshOnLockFail bool
+
+	// LockWaitTimeout 取分布式锁失败后轮询缓存等待持锁方写入 token 的最长时间；0 表示不等待
+	LockWaitTimeout time.Duration
+
+	// BaseURL 微信 API 根地址；默认 https://api.weixin.qq.com
+	BaseURL string
+
+	// Logger 日志输出（可选），用于告警类提示
+	Logger Logger
+
+	// Metrics 指标回调（可选）
+	Metrics Metrics
+
+	// Clock 时间源（可选），默认系统时间；用于测试中控制过期判断
+	Clock Clock
+
+	// KeyPrefix 缓存/锁 key 前缀；默认 wxgo
+	KeyPrefix string
+
+	// RedisFastPath 是否开启 Redis 快速路径：以 Lua 脚本一次往返完成「读缓存 + 未命中时占锁」
+	// 仅在缓存与锁均为内置 Redis/集群实现时生效；集群上须同时开启 KeyHashTag
+	RedisFastPath bool
+
+	// KeyHashTag 是否以 Redis Cluster hash tag 包裹 key 中的 AppID（{prefix}:token:{appID}），
+	// 使同一 AppID 的缓存 key 与锁 key 落在同一 slot；默认关闭，保持原有 key 布局
+	KeyHashTag bool
+
+	// DefaultTimeout 调用方 ctx 无截止时间时，单次操作的默认超时；默认 10s
+	DefaultTimeout time.Duration
+
+	// MaxResponseBytes 获取 token 时响应体大小上限；默认 10MB
+	MaxResponseBytes int64
+
+	// HTTPClient 获取 token 使用的 HTTP 客户端；默认 transport.NewClient()
+	HTTPClient *transport.Client
+
+	// TTLJitter 缓存 TTL 与提前刷新窗口的随机抖动比例，取值 [0,1)，超出时 Validate 报错；默认 0 不抖动
+	TTLJitter float64
+
+	// HotCacheTTL 进程内保留已解析 token 副本的时长，期间命中不访问共享缓存、不反序列化；0 关闭，取值 [0, 1m]
+	HotCacheTTL time.Duration
+
+	// EarlyRefresh 提前刷新窗口；nil 使用默认 5 分钟，指向 0 时仅在 token 实际过期后刷新，负数报错
+	EarlyRefresh *time.Duration
+
+	// CacheTTLPadding 写入缓存时在 TTL 上额外增加的时长（不影响 ExpiresAt），使过期 token 可供 StaleIfError 兜底读取；取值 [0, 1h]
+	CacheTTLPadding time.Duration
+
+	// StaleIfError 网络/5xx 等导致获取失败时，若缓存中有过期不超过 staleWindow 的 token 则以 CodeStale 返回它
+	StaleIfError bool
+
+	// BackgroundRefresh 是否启动后台协程，在 token 进入提前刷新窗口时主动刷新；需调用 Manager.Close 停止
+	BackgroundRefresh bool
+
+	// NegativeCache 是否开启负缓存：AppID/AppSecret 错误等不可重试的失败在 NegativeCacheTTL 内直接返回
+	NegativeCache bool
+
+	// NegativeCacheTTL 负缓存时长；默认 5s
+	NegativeCacheTTL time.Duration
 }
 
+// RedisPreference 同时配置 Redis 单点与集群客户端时的选择
+type RedisPreference string
+
+const (
+	// PreferRedisCluster 优先使用 RedisClusterClient（默认）
+	PreferRedisCluster RedisPreference = "cluster"
+	// PreferRedisSingle 优先使用 RedisClient
+	PreferRedisSingle RedisPreference = "single"
+)
+
 // Validate 验证配置是否有效
+// 会去掉 AppID/AppSecret 首尾空白（常见于从后台复制粘贴），内部仍含空白或控制字符则报错
 func (c *Config) Validate() error {
+	if c.provider() == ProviderWorkWeChat {
+		// 企业微信复用 AppID/AppSecret 作为 corpid/corpsecret，缓存/锁 key 随之以 corpid 区分
+		if c.AppID == "" {
+			c.AppID = c.CorpID
+		}
+		if c.AppSecret == "" {
+			c.AppSecret = c.CorpSecret
+		}
+	}
+	c.AppID = strings.TrimSpace(c.AppID)
+	c.AppSecret = strings.TrimSpace(c.AppSecret)
+
 	if c.AppID == "" {
 		return ErrMissingAppID
 	}
-	if c.AppSecret == "" {
+	if c.AppSecret == "" && c.SecretProvider == nil && c.Fetcher == nil {
 		return ErrMissingAppSecret
 	}
+	if c.provider() == ProviderWorkWeChat {
+		if err := c.setWorkScope(); err != nil {
+			return err
+		}
+	}
+	if c.provider() == ProviderComponent && c.ComponentVerifyTicket == nil && c.Fetcher == nil {
+		return ErrMissingVerifyTicket
+	}
+	if c.RedisReadClient != nil && c.RedisClient == nil {
+		return ErrReadClientWithoutWriter
+	}
+	switch c.PreferRedis {
+	case "", PreferRedisCluster, PreferRedisSingle:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidPreferRedis, c.PreferRedis)
+	}
+	if c.StrictBackend {
+		if err := c.checkSingleBackend(); err != nil {
+			return err
+		}
+	}
+	if c.EarlyRefresh != nil && *c.EarlyRefresh < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidEarlyRefresh, *c.EarlyRefresh)
+	}
+	if c.HotCacheTTL < 0 || c.HotCacheTTL > maxHotCacheTTL {
+		return fmt.Errorf("%w: %s", ErrInvalidHotCacheTTL, c.HotCacheTTL)
+	}
+	if c.CacheTTLPadding < 0 || c.CacheTTLPadding > maxCacheTTLPadding {
+		return fmt.Errorf("%w: %s", ErrInvalidTTLPadding, c.CacheTTLPadding)
+	}
+	// 写成取反形式，NaN 同样报错
+	if !(c.TTLJitter >= 0 && c.TTLJitter < 1) {
+		return fmt.Errorf("%w: %v", ErrInvalidTTLJitter, c.TTLJitter)
+	}
+	if hasInvalidChar(c.AppID) {
+		return ErrInvalidAppID
+	}
+	if hasInvalidChar(c.AppSecret) {
+		return ErrInvalidAppSecret
+	}
 	return nil
 }
 
+// checkSingleBackend 检查至多配置了一个缓存来源，冲突时在错误中列出全部已设置的字段
+func (c *Config) checkSingleBackend() error {
+	var set []string
+	if c.Cache != nil {
+		set = append(set, "Cache")
+	}
+	if c.RedisClusterClient != nil {
+		set = append(set, "RedisClusterClient")
+	}
+	if c.RedisClient != nil {
+		set = append(set, "RedisClient")
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("%w: %s are set, keep only one", ErrMultipleBackends, strings.Join(set, " and "))
+	}
+	return nil
+}
+
+// secret 返回本次获取 token 使用的 AppSecret：配置了 SecretProvider 时以其返回值为准
+func (c *Config) secret(ctx context.Context) (string, error) {
+	if c.SecretProvider == nil {
+		return c.AppSecret, nil
+	}
+	secret, err := c.SecretProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("secret provider: %w", err)
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", ErrMissingAppSecret
+	}
+	if hasInvalidChar(secret) {
+		return "", ErrInvalidAppSecret
+	}
+	return secret, nil
+}
+
+// looksLikeAppID 粗略判断 AppID 格式：公众号/小程序为 wx 开头，原始 ID 为 gh_ 开头
+// 仅用于告警，不拒绝其他格式
+func (c *Config) looksLikeAppID() bool {
+	if c.provider() == ProviderWorkWeChat {
+		return true
+	}
+	return strings.HasPrefix(c.AppID, "wx") || strings.HasPrefix(c.AppID, "gh_")
+}
+
+// hasInvalidChar 是否包含空白或控制字符
+func hasInvalidChar(s string) bool {
+	for _, r := range s {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCache 获取缓存实现（按优先级选择）
 // 优先级：Cache > RedisClusterClient > RedisClient > 内存
 // 即便多种同时传入，也按优先级选定一个，不报错
@@ -45,12 +289,26 @@ func (c *Config) GetCache() Cache {
 		return NewRedisClusterCache(c.RedisClusterClient)
 	}
 	if c.RedisClient != nil {
-		return NewRedisCache(c.RedisClient)
+		return NewRedisCacheWithReader(c.RedisClient, c.RedisReadClient)
 	}
 	// 默认使用内存缓存
 	return NewMemoryCache()
 }
 
+// lockRedis 返回锁专用的 Redis 客户端；单点与集群同时设置时按 PreferRedis 选择，默认集群
+func (c *Config) lockRedis() redis.Cmdable {
+	if c.PreferRedis == PreferRedisSingle && c.LockRedisClient != nil {
+		return c.LockRedisClient
+	}
+	if c.LockRedisClusterClient != nil {
+		return c.LockRedisClusterClient
+	}
+	if c.LockRedisClient != nil {
+		return c.LockRedisClient
+	}
+	return nil
+}
+
 // lockStrategy 返回有效的分布式锁策略，默认 auto
 func (c *Config) lockStrategy() DistLockStrategy {
 	if c.DistLockStrategy == "" {
@@ -58,3 +316,108 @@ func (c *Config) lockStrategy() DistLockStrategy {
 	}
 	return c.DistLockStrategy
 }
+
+// effectiveKeyPrefix 返回有效的 key 前缀，空值时为默认前缀 wxgo
+func effectiveKeyPrefix(prefix string) string {
+	if prefix == "" {
+		return defaultKeyPrefix
+	}
+	return prefix
+}
+
+// credential 返回缓存/锁 key 中的凭据名
+func (c *Config) credential() string {
+	switch {
+	case c.Credential != "":
+		return c.Credential
+	case c.provider() == ProviderComponent:
+		return "component_token"
+	default:
+		return "token"
+	}
+}
+
+// setWorkScope 设置企业微信的 appScope：同一企业下各应用共用 corpid，需以 agentid 区分，否则以 secret 指纹区分
+// 指纹取 Validate 时的 secret，之后 RotateSecret 不改变 key；各实例以不同 secret 启动时 key 不同，建议设置 AgentID
+func (c *Config) setWorkScope() error {
+	c.AgentID = strings.TrimSpace(c.AgentID)
+	switch {
+	case c.AgentID != "":
+		c.appScope = c.AgentID
+	case c.AppSecret != "":
+		if c.appScope == "" {
+			c.appScope = SecretScope(c.AppSecret)
+		}
+	case c.SecretProvider != nil:
+		return ErrMissingAgentID
+	}
+	return nil
+}
+
+// SecretScope 返回企业微信未设置 AgentID 时 key 中使用的 secret 指纹（"s" + sha256 前 4 字节的十六进制）
+func SecretScope(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return "s" + hex.EncodeToString(sum[:4])
+}
+
+// keyParts 返回本配置的缓存/锁 key 组成部分，getCacheKey、getLockKey 等均由它生成
+func (c *Config) keyParts() KeyParts {
+	return KeyParts{
+		Prefix:     c.KeyPrefix,
+		Credential: c.credential(),
+		AppID:      c.AppID,
+		Scope:      c.appScope,
+		HashTag:    c.KeyHashTag,
+	}
+}
+
+// negativeCacheTTL 返回负缓存时长；未开启时为 0
+func (c *Config) negativeCacheTTL() time.Duration {
+	if !c.NegativeCache {
+		return 0
+	}
+	if c.NegativeCacheTTL <= 0 {
+		return defaultNegativeCacheTTL
+	}
+	return c.NegativeCacheTTL
+}
+
+// earlyRefresh 返回本实例的提前刷新窗口：默认 5 分钟，开启 TTLJitter 时随机放大 [0, jitter] 比例
+// 每个实例在创建时各自取值，使集群中各实例错开刷新时间；EarlyRefresh 为 0 时窗口恒为 0
+func (c *Config) earlyRefresh() time.Duration {
+	base := defaultEarlyRefresh
+	if c.EarlyRefresh != nil {
+		base = *c.EarlyRefresh
+	}
+	return base + time.Duration(float64(base)*c.TTLJitter*randv2.Float64())
+}
+
+// maxCacheTTLPadding CacheTTLPadding 的上限；微信 token 有效期为 2 小时，padding 应远小于它
+const maxCacheTTLPadding = time.Hour
+
+// defaultStaleWindow 开启 StaleIfError 但未设置 CacheTTLPadding 时，过期 token 在缓存中保留、可用于兜底的时长
+const defaultStaleWindow = 5 * time.Minute
+
+// staleWindow 过期 token 在缓存中保留、可供 StaleIfError 兜底的时长：CacheTTLPadding，未设置时开启 StaleIfError 则为 5 分钟，否则为 0
+func (c *Config) staleWindow() time.Duration {
+	if c.CacheTTLPadding > 0 {
+		return c.CacheTTLPadding
+	}
+	if c.StaleIfError {
+		return defaultStaleWindow
+	}
+	return 0
+}
+
+// cacheTTL 返回写入缓存的 TTL：无 stale 窗口时为 expires_in 随机缩短 [0, jitter] 比例；
+// 有窗口时为 max(抖动后的 TTL, expires_in) + staleWindow，抖动不会抵消窗口，缓存至少保留到 ExpiresAt 之后窗口时长
+// 窗口仅延长缓存保留时间，token 是否过期仍以 ExpiresAt 判断
+func (c *Config) cacheTTL(expiresIn int) time.Duration {
+	ttl := secondsDuration(expiresIn)
+	jittered := ttl - time.Duration(float64(ttl)*c.TTLJitter*randv2.Float64())
+	window := c.staleWindow()
+	if window <= 0 {
+		return jittered
+	}
+	return max(jittered, ttl) + window
+}
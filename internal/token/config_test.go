@@ -0,0 +1,157 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheTTLPaddingSurvivesJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		jitter  float64
+		padding time.Duration
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"no jitter no padding", 0, 0, 7200 * time.Second, 7200 * time.Second},
+		{"jitter only", 0.5, 0, 3600 * time.Second, 7200 * time.Second},
+		{"padding only", 0, 5 * time.Minute, 7200*time.Second + 5*time.Minute, 7200*time.Second + 5*time.Minute},
+		{"jitter and padding", 0.99, 5 * time.Minute, 7200*time.Second + 5*time.Minute, 7200*time.Second + 5*time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{TTLJitter: tt.jitter, CacheTTLPadding: tt.padding}
+			for i := 0; i < 200; i++ {
+				if got := c.cacheTTL(7200); got < tt.min || got > tt.max {
+					t.Fatalf("cacheTTL = %s, want within [%s, %s]", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkWeChatKeyIDSeparatesApps(t *testing.T) {
+	keyID := func(cfg Config) string {
+		t.Helper()
+		cfg.Provider = ProviderWorkWeChat
+		cfg.CorpID = "ww_corp"
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+		return cfg.keyParts().id()
+	}
+
+	if got := keyID(Config{CorpSecret: "secret-a", AgentID: "1000002"}); got != "ww_corp:1000002" {
+		t.Fatalf("keyID with AgentID = %q, want ww_corp:1000002", got)
+	}
+	if got := keyID(Config{CorpSecret: "secret-a", AgentID: "1000002", KeyHashTag: true}); got != "{ww_corp:1000002}" {
+		t.Fatalf("keyID with hash tag = %q, want {ww_corp:1000002}", got)
+	}
+
+	// 未设置 AgentID：同一企业下不同 secret 的应用 key 不同，相同 secret 的实例 key 相同
+	a, b := keyID(Config{CorpSecret: "secret-a"}), keyID(Config{CorpSecret: "secret-b"})
+	if a == b || a != "ww_corp:"+SecretScope("secret-a") || strings.Contains(a, "secret-a") {
+		t.Fatalf("fingerprint keyIDs = %q, %q", a, b)
+	}
+	if again := keyID(Config{CorpSecret: "secret-a"}); again != a {
+		t.Fatalf("keyID not stable: %q != %q", again, a)
+	}
+
+	cfg := Config{Provider: ProviderWorkWeChat, CorpID: "ww_corp", SecretProvider: func(context.Context) (string, error) { return "s", nil }}
+	if err := cfg.Validate(); !errors.Is(err, ErrMissingAgentID) {
+		t.Fatalf("Validate with SecretProvider = %v, want ErrMissingAgentID", err)
+	}
+
+	// 公众号 key 不变
+	mp := Config{AppID: "wx_mp", AppSecret: "secret"}
+	if err := mp.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got := mp.keyParts().id(); got != "wx_mp" {
+		t.Fatalf("mp keyID = %q, want wx_mp", got)
+	}
+}
+
+// TestExportedKeysMatchManager 导出的 key 函数与 Manager 实际读写的 key 一致
+func TestExportedKeysMatchManager(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		parts KeyParts
+	}{
+		{"default", Config{AppID: "wx_keys", AppSecret: "secret"}, KeyParts{AppID: "wx_keys"}},
+		{"prefix", Config{AppID: "wx_keys", AppSecret: "secret", KeyPrefix: "svc"}, KeyParts{Prefix: "svc", AppID: "wx_keys"}},
+		{"hash tag", Config{AppID: "wx_keys", AppSecret: "secret", KeyHashTag: true}, KeyParts{AppID: "wx_keys", HashTag: true}},
+		{"credential", Config{AppID: "wx_keys", AppSecret: "secret", Credential: "jsapi_ticket"}, KeyParts{Credential: "jsapi_ticket", AppID: "wx_keys"}},
+		{
+			"work wechat agent",
+			Config{Provider: ProviderWorkWeChat, AppID: "ww_corp", AppSecret: "secret", AgentID: "1000002"},
+			KeyParts{AppID: "ww_corp", Scope: "1000002"},
+		},
+		{
+			"work wechat fingerprint",
+			Config{Provider: ProviderWorkWeChat, AppID: "ww_corp", AppSecret: "secret", KeyPrefix: "svc", KeyHashTag: true},
+			KeyParts{Prefix: "svc", AppID: "ww_corp", Scope: SecretScope("secret"), HashTag: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			m, err := newTestManager(&cfg, &stubFetcher{})
+			if err != nil {
+				t.Fatalf("NewManager: %v", err)
+			}
+			defer m.Close()
+
+			if got, want := tt.parts.CacheKey(), m.getCacheKey(); got != want {
+				t.Fatalf("KeyParts.CacheKey = %q, manager uses %q", got, want)
+			}
+			if got, want := tt.parts.LockKey(), m.getLockKey(); got != want {
+				t.Fatalf("KeyParts.LockKey = %q, manager uses %q", got, want)
+			}
+		})
+	}
+
+	// CacheKey/LockKey（wxgo.TokenCacheKey/TokenLockKey）对 access_token 的 key 与 Manager 一致
+	for _, cfg := range []Config{
+		{AppID: "wx_keys", AppSecret: "secret"},
+		{AppID: "wx_keys", AppSecret: "secret", KeyPrefix: "svc"},
+		{AppID: "wx_keys", AppSecret: "secret", KeyHashTag: true},
+	} {
+		appID := cfg.AppID
+		if cfg.KeyHashTag {
+			appID = "{" + appID + "}"
+		}
+		prefix := cfg.KeyPrefix
+		m, err := newTestManager(&cfg, &stubFetcher{})
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		if got, want := CacheKey(appID, prefix), m.getCacheKey(); got != want {
+			t.Errorf("CacheKey(%q, %q) = %q, manager uses %q", appID, prefix, got, want)
+		}
+		if got, want := LockKey(appID, prefix), m.getLockKey(); got != want {
+			t.Errorf("LockKey(%q, %q) = %q, manager uses %q", appID, prefix, got, want)
+		}
+		m.Close()
+	}
+}
+
+func TestValidateRejectsOutOfRangeTTLJitter(t *testing.T) {
+	for _, jitter := range []float64{-0.1, 1, 1.5, math.NaN()} {
+		cfg := Config{AppID: "wx_jitter", AppSecret: "secret", TTLJitter: jitter}
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidTTLJitter) {
+			t.Errorf("Validate(TTLJitter=%v) = %v, want ErrInvalidTTLJitter", jitter, err)
+		}
+	}
+	for _, jitter := range []float64{0, 0.1, 0.99} {
+		cfg := Config{AppID: "wx_jitter", AppSecret: "secret", TTLJitter: jitter}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate(TTLJitter=%v) = %v, want nil", jitter, err)
+		}
+	}
+}
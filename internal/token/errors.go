@@ -1,6 +1,15 @@
 package token
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
 
 // Code 机器可读的错误码，便于上层做国际化或分支处理
 type Code string
@@ -12,18 +21,48 @@ const (
 	CodeMissingAppID Code = "E_MISSING_APP_ID"
 	// CodeMissingAppSecret 缺少 AppSecret
 	CodeMissingAppSecret Code = "E_MISSING_APP_SECRET"
+	// CodeMissingVerifyTicket 第三方平台缺少 component_verify_ticket（未配置回调或回调未返回）
+	CodeMissingVerifyTicket Code = "E_MISSING_VERIFY_TICKET"
 	// CodeCacheGet 缓存读取失败
 	CodeCacheGet Code = "E_CACHE_GET"
 	// CodeCacheSet 缓存写入失败
 	CodeCacheSet Code = "E_CACHE_SET"
+	// CodeCacheDelete 缓存删除失败
+	CodeCacheDelete Code = "E_CACHE_DELETE"
 	// CodeHTTP 调用 HTTP 失败
 	CodeHTTP Code = "E_HTTP"
 	// CodeAPIError 微信 API 返回错误
 	CodeAPIError Code = "E_WECHAT_API"
 	// CodeInvalidResponse 响应解析失败
 	CodeInvalidResponse Code = "E_INVALID_RESPONSE"
+	// CodeNonJSONResponse 微信返回了非 JSON 响应（如故障期间的 HTML 错误页、空响应）
+	CodeNonJSONResponse Code = "E_NON_JSON_RESPONSE"
+	// CodeResponseTooLarge 响应体超过 MaxResponseBytes/MaxMediaBytes 上限
+	CodeResponseTooLarge Code = "E_RESPONSE_TOO_LARGE"
 	// CodeLock 分布式锁获取失败
 	CodeLock Code = "E_LOCK"
+	// CodeInvalidParam 请求参数不合法（客户端校验未通过）
+	CodeInvalidParam Code = "E_INVALID_PARAM"
+	// CodeUserRefused 用户拒绝接收消息（errcode=43101）
+	CodeUserRefused Code = "E_USER_REFUSED"
+	// CodeInvalidOpenID openid 不合法或不属于当前 AppID（errcode=40003）
+	CodeInvalidOpenID Code = "E_INVALID_OPENID"
+	// CodeIPNotWhitelisted 调用方出口 IP 不在公众号 IP 白名单中（errcode=40164）
+	CodeIPNotWhitelisted Code = "E_IP_NOT_WHITELISTED"
+	// CodeInvalidDateRange 数据统计接口的日期范围不合法（errcode=61501，如跨度超限或结束日期不早于今天）
+	CodeInvalidDateRange Code = "E_INVALID_DATE_RANGE"
+	// CodeStale 获取新 token 失败，返回的是缓存中已过期的 token（StaleIfError 兜底，尽力而为）
+	CodeStale Code = "E_STALE"
+	// CodeForceRefreshThrottled 稳定版 token 的强制刷新超出频率限制，已退回普通获取（返回的 token 可用）
+	CodeForceRefreshThrottled Code = "E_FORCE_REFRESH_THROTTLED"
+	// CodeUnionIDUnavailable 微信未返回 unionid（公众号未绑定到微信开放平台帐号）
+	CodeUnionIDUnavailable Code = "E_UNIONID_UNAVAILABLE"
+	// CodeNotCached 缓存中没有 token（只读缓存的查询接口返回）
+	CodeNotCached Code = "E_NOT_CACHED"
+	// CodeCanceled 调用方取消了 ctx
+	CodeCanceled Code = "E_CANCELED"
+	// CodeTimeout 调用方 ctx 已超过截止时间
+	CodeTimeout Code = "E_TIMEOUT"
 	// CodeUnknown 未分类错误
 	CodeUnknown Code = "E_UNKNOWN"
 )
@@ -35,15 +74,210 @@ var (
 	// ErrMissingAppSecret AppSecret 未设置
 	ErrMissingAppSecret = errors.New("wxgo: app_secret is required")
 
+	// ErrMissingVerifyTicket 第三方平台未提供 component_verify_ticket
+	ErrMissingVerifyTicket = errors.New("wxgo: component_verify_ticket is required")
+
+	// ErrMissingAgentID 企业微信使用 SecretProvider 时未设置 AgentID，无法区分同一企业下各应用的缓存 key
+	ErrMissingAgentID = errors.New("wxgo: agent_id is required for work wechat with a secret provider")
+
+	// ErrNotComponentProvider 当前 Client 不是第三方平台（ProviderComponent）
+	ErrNotComponentProvider = errors.New("wxgo: client is not configured for the component provider")
+
+	// ErrInvalidAppID AppID 含空白或控制字符
+	ErrInvalidAppID = errors.New("wxgo: app_id contains whitespace or control characters")
+
+	// ErrInvalidAppSecret AppSecret 含空白或控制字符
+	ErrInvalidAppSecret = errors.New("wxgo: app_secret contains whitespace or control characters")
+
 	// ErrInvalidResponse 微信返回的响应无效
 	ErrInvalidResponse = errors.New("wxgo: invalid response from wechat api")
 
+	// ErrNonJSONResponse 微信返回了非 JSON 响应，通常是微信侧故障
+	ErrNonJSONResponse = errors.New("wxgo: wechat api returned non-json response")
+
+	// ErrResponseTooLarge 响应体超过大小上限
+	ErrResponseTooLarge = transport.ErrResponseTooLarge
+
 	// ErrAPIError 微信 API 返回错误
 	ErrAPIError = errors.New("wxgo: api error")
 
+	// ErrTokenNotCached 缓存中没有 token
+	ErrTokenNotCached = errors.New("wxgo: token not cached")
+
+	// ErrInvalidTTL 写入缓存的 TTL 非正数（会导致 Redis key 永不过期）
+	ErrInvalidTTL = errors.New("wxgo: cache ttl must be positive")
+
+	// ErrFlushNotSupported 缓存未实现 Flusher，无法按前缀批量删除
+	ErrFlushNotSupported = errors.New("wxgo: cache does not support flush")
+
 	// ErrLockAcquire 分布式锁获取失败
 	ErrLockAcquire = errors.New("wxgo: acquire distributed lock failed")
 
+	// ErrLockOverrun 持锁时间超过锁 TTL（仅作诊断，由解锁函数返回，不影响本次结果）
+	ErrLockOverrun = errors.New("wxgo: distributed lock held longer than its ttl")
+
+	// ErrInvalidEarlyRefresh EarlyRefresh 为负数
+	ErrInvalidEarlyRefresh = errors.New("wxgo: early refresh must not be negative")
+	// ErrInvalidHotCacheTTL HotCacheTTL 超出 [0, 1m]
+	ErrInvalidHotCacheTTL = errors.New("wxgo: hot cache ttl must be within [0, 1m]")
+	// ErrInvalidTTLPadding CacheTTLPadding 超出 [0, 1h]
+	ErrInvalidTTLPadding = errors.New("wxgo: cache ttl padding must be within [0, 1h]")
+	// ErrInvalidTTLJitter TTLJitter 超出 [0, 1)
+	ErrInvalidTTLJitter = errors.New("wxgo: ttl jitter must be within [0, 1)")
+
+	// ErrReadClientWithoutWriter 设置了 RedisReadClient 但未设置 RedisClient
+	ErrReadClientWithoutWriter = errors.New("wxgo: RedisReadClient requires RedisClient for writes and locking")
+
+	// ErrInvalidPreferRedis PreferRedis 不是 cluster/single
+	ErrInvalidPreferRedis = errors.New("wxgo: prefer redis must be \"cluster\" or \"single\"")
+
+	// ErrMultipleBackends StrictBackend 下同时配置了多个缓存来源
+	ErrMultipleBackends = errors.New("wxgo: multiple cache backends configured")
+
+	// ErrFastPathNeedsHashTag Redis 集群上开启 RedisFastPath 但未开启 KeyHashTag，缓存 key 与锁 key 不在同一 slot
+	ErrFastPathNeedsHashTag = errors.New("wxgo: redis fast path on cluster requires KeyHashTag")
+
 	// ErrLockBackendMissing 需要分布式锁但未配置可用后端
 	ErrLockBackendMissing = errors.New("wxgo: distributed lock required but no backend available")
 )
+
+// ContextCode 返回 ctx 结束对应的错误码：超时为 CodeTimeout，其余为 CodeCanceled
+func ContextCode(err error) Code {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	return CodeCanceled
+}
+
+// isContextErr 是否为 ctx 取消或超时导致的错误
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// RequestErrorCode 返回发送请求失败对应的错误码：请求的 ctx 已取消或超时为 CodeCanceled/CodeTimeout，否则为 CodeHTTP
+// 以 ctx 而非 err 判断，HTTPTimeout 等传输层超时仍归为 CodeHTTP；便于区分「调用方放弃」与「微信不可达」
+func RequestErrorCode(ctx context.Context, err error) Code {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ContextCode(ctxErr)
+	}
+	return CodeHTTP
+}
+
+// ReadErrorCode 返回读取响应体失败对应的错误码：超过大小上限为 CodeResponseTooLarge，
+// ctx 已取消或超时为 CodeCanceled/CodeTimeout，否则为 fallback
+func ReadErrorCode(ctx context.Context, err error, fallback Code) Code {
+	if errors.Is(err, ErrResponseTooLarge) {
+		return CodeResponseTooLarge
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ContextCode(ctxErr)
+	}
+	return fallback
+}
+
+// NonJSONError 响应体不是 JSON 时返回带片段的 ErrNonJSONResponse，否则返回 nil
+// 片段已截断并抹去敏感参数，可直接写入日志
+func NonJSONError(body []byte) error {
+	if transport.LooksLikeJSON(body) {
+		return nil
+	}
+	if len(transport.TrimJSONBody(body)) == 0 {
+		return fmt.Errorf("%w: empty body", ErrNonJSONResponse)
+	}
+	return fmt.Errorf("%w: %q", ErrNonJSONResponse, transport.BodySnippet(body))
+}
+
+// DecodeWeChatResponse 统一解析微信接口的 JSON 响应，所有接口共用同一套错误分类：
+// 非 JSON（如故障期间的 HTML 错误页）返回 CodeNonJSONResponse；errcode 非 0 返回 CodeAPIError 与 *APIError（含 rid）；
+// 否则解析到 out（out 为 nil 时只检查 errcode），解析失败返回 CodeInvalidResponse 与 ErrInvalidResponse。
+// 不依赖 Content-Type（微信可能以 text/plain 返回），解析前去掉 BOM 与首尾空白
+func DecodeWeChatResponse(body []byte, out any) (Code, error) {
+	if err := NonJSONError(body); err != nil {
+		return CodeNonJSONResponse, err
+	}
+	body = transport.TrimJSONBody(body)
+
+	var apiResp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		RID     any    `json:"rid"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return CodeInvalidResponse, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if apiResp.ErrCode != 0 {
+		return CodeAPIError, NewAPIError(apiResp.ErrCode, apiResp.ErrMsg, apiResp.RID)
+	}
+
+	if out == nil {
+		return CodeOK, nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return CodeInvalidResponse, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return CodeOK, nil
+}
+
+// APIError 微信 API 返回的业务错误（errcode 非 0）
+// 可通过 errors.Is(err, ErrAPIError) 判断，或 errors.As 取出 errcode
+type APIError struct {
+	ErrCode int
+	ErrMsg  string
+	// RID 微信返回的请求 ID，向微信支持反馈问题时提供；取自响应的 rid 字段或 errmsg 中的 "rid: xxx"，可能为空
+	RID string
+}
+
+// ridPattern 从 errmsg（如 "invalid credential rid: 6650a1b2-1c2d3e4f-5a6b7c8d"）中提取 rid
+var ridPattern = regexp.MustCompile(`rid:\s*([0-9A-Za-z-]+)`)
+
+// NewAPIError 由响应中的 errcode/errmsg/rid 构建 APIError
+// rid 为响应 JSON 中的原始值（字段缺失时为 nil，类型不定时按字面转为字符串），为空时尝试从 errmsg 中提取
+func NewAPIError(errCode int, errMsg string, rid any) *APIError {
+	e := &APIError{ErrCode: errCode, ErrMsg: errMsg}
+	switch v := rid.(type) {
+	case nil:
+	case string:
+		e.RID = strings.TrimSpace(v)
+	default:
+		e.RID = fmt.Sprint(v)
+	}
+	if e.RID == "" {
+		if m := ridPattern.FindStringSubmatch(errMsg); m != nil {
+			e.RID = m[1]
+		}
+	}
+	return e
+}
+
+// Error 实现 error 接口；rid 未出现在 errmsg 中时附在末尾
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s: errcode=%d, errmsg=%s", ErrAPIError.Error(), e.ErrCode, e.ErrMsg)
+	if e.RID != "" && !strings.Contains(e.ErrMsg, e.RID) {
+		msg += ", rid=" + e.RID
+	}
+	return msg
+}
+
+// Unwrap 使 errors.Is(err, ErrAPIError) 成立
+func (e *APIError) Unwrap() error {
+	return ErrAPIError
+}
+
+const (
+	// errCodeIPNotWhitelisted 出口 IP 不在白名单
+	errCodeIPNotWhitelisted = 40164
+	// errCodeSystemBusy 微信系统繁忙
+	errCodeSystemBusy = -1
+)
+
+// whitelistIPPattern 从 errmsg（如 "invalid ip 1.2.3.4 ipv6 ::ffff:1.2.3.4, not in whitelist"）中提取出口 IP
+var whitelistIPPattern = regexp.MustCompile(`invalid ip ([0-9A-Fa-f.:]+)`)
+
+// ipWhitelistError 为 40164 附加排查建议；保留 *APIError 以便 errors.As
+func ipWhitelistError(apiErr *APIError) error {
+	hint := "add this server's egress IP to the IP whitelist in the WeChat admin console"
+	if m := whitelistIPPattern.FindStringSubmatch(apiErr.ErrMsg); m != nil {
+		hint = fmt.Sprintf("add egress IP %s to the IP whitelist in the WeChat admin console", m[1])
+	}
+	return fmt.Errorf("%w (%s)", apiErr, hint)
+}
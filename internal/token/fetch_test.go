@@ -0,0 +1,36 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchTokenToleratesBOMAndTextPlain(t *testing.T) {
+	bodies := map[string]string{
+		"bom":                 "\ufeff" + `{"access_token":"tk","expires_in":7200}`,
+		"bom and whitespace":  "\ufeff \r\n" + `{"access_token":"tk","expires_in":7200}` + "\n\n",
+		"trailing whitespace": `{"access_token":"tk","expires_in":7200}` + " \t\r\n",
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			m, err := NewManager(&Config{AppID: "wx_bom", AppSecret: "secret", BaseURL: srv.URL, Cache: NewMemoryCache()})
+			if err != nil {
+				t.Fatalf("NewManager: %v", err)
+			}
+			defer m.Close()
+
+			tk, code, err := m.GetAccessToken(context.Background())
+			if err != nil || code != CodeOK || tk != "tk" {
+				t.Fatalf("GetAccessToken = %q, %v, %v", tk, code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package token
+
+import (
+	"context"
+	"fmt"
+)
+
+// TokenFetcher 获取 token 的实现，Manager 负责缓存、锁与刷新编排，实际获取委托给它
+// 默认实现直接请求微信；集中式 token 服务（broker）部署中可替换为从内部服务获取
+// Fetch 仅在持有本地锁（及分布式锁）时调用，返回的 TokenInfo 需设置 ExpiresIn 或 ExpiresAt
+type TokenFetcher interface {
+	Fetch(ctx context.Context) (*TokenInfo, Code, error)
+}
+
+// wechatFetcher 默认实现：使用 refresh 中解析出的 secret 请求微信
+type wechatFetcher struct {
+	m *Manager
+}
+
+// Fetch 从微信获取 token；secret 由 refresh 在调用前解析并保存在 lastSecret
+func (f wechatFetcher) Fetch(ctx context.Context) (*TokenInfo, Code, error) {
+	return f.m.fetchTokenFromWeChat(ctx, f.m.lastSecret)
+}
+
+// fetch 调用 fetcher 并校验结果，补齐自定义实现未设置的 ExpiresAt/ExpiresIn
+func (m *Manager) fetch(ctx context.Context) (*TokenInfo, Code, error) {
+	token, code, err := m.fetcher.Fetch(ctx)
+	if err != nil {
+		if code == CodeOK || code == "" {
+			code = CodeUnknown
+		}
+		return nil, code, err
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, CodeInvalidResponse, fmt.Errorf("%w: fetcher returned empty token", ErrInvalidResponse)
+	}
+
+	now := m.clock.Now()
+	switch {
+	case token.ExpiresAt.IsZero() && token.ExpiresIn > 0:
+		token.ExpiresAt = now.Add(secondsDuration(token.ExpiresIn))
+	case token.ExpiresIn <= 0 && token.ExpiresAt.After(now):
+		token.ExpiresIn = int(token.ExpiresAt.Sub(now).Seconds())
+	case token.ExpiresIn <= 0:
+		return nil, CodeInvalidResponse, fmt.Errorf("%w: fetcher returned token without expiry", ErrInvalidResponse)
+	}
+	return token, CodeOK, nil
+}
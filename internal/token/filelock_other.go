@@ -0,0 +1,15 @@
+//go:build !unix
+
+package token
+
+import "os"
+
+// lockFile 非 Unix 平台不提供跨进程文件锁，仅有进程内互斥
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile 非 Unix 平台为空操作
+func unlockFile(f *os.File) error {
+	return nil
+}
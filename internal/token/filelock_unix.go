@@ -0,0 +1,18 @@
+//go:build unix
+
+package token
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile 对 f 加排他锁（flock），阻塞直到获得；进程退出时由系统释放
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+// unlockFile 释放 lockFile 加的锁
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,132 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stubFetcher 返回递增编号 token 的 TokenFetcher，记录调用次数
+type stubFetcher struct {
+	calls     atomic.Int32
+	expiresIn int
+	err       error
+	code      Code
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context) (*TokenInfo, Code, error) {
+	n := f.calls.Add(1)
+	if f.err != nil {
+		return nil, f.code, f.err
+	}
+	expiresIn := f.expiresIn
+	if expiresIn == 0 {
+		expiresIn = 7200
+	}
+	return &TokenInfo{AccessToken: fmt.Sprintf("token-%d", n), ExpiresIn: expiresIn}, CodeOK, nil
+}
+
+// fakeClock 可手动推进的时钟，实现 TimerClock：Advance 时触发到期的计时器
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer fakeClock 创建的计时器
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	d        time.Duration
+	ch       chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), d: d, ch: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance 推进时钟并触发到期的计时器
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if c.now.Before(t.deadline) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+}
+
+func (c *fakeClock) remove(t *fakeTimer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, p := range c.timers {
+		if p == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// waitTimer 等待出现一个待触发的计时器并返回其时长；超时返回 false
+func (c *fakeClock) waitTimer(timeout time.Duration) (time.Duration, bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		if len(c.timers) > 0 {
+			d := c.timers[0].d
+			c.mu.Unlock()
+			return d, true
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	return 0, false
+}
+
+// newTestManager 使用内存缓存与 stubFetcher 创建 Manager；cfg 可为 nil
+func newTestManager(cfg *Config, f TokenFetcher) (*Manager, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	if cfg.AppID == "" {
+		cfg.AppID = "wx_test"
+	}
+	if cfg.AppSecret == "" && cfg.SecretProvider == nil {
+		cfg.AppSecret = "secret-0001"
+	}
+	if cfg.Cache == nil && cfg.RedisClient == nil && cfg.RedisClusterClient == nil {
+		cfg.Cache = NewMemoryCache()
+	}
+	cfg.Fetcher = f
+	return NewManager(cfg)
+}
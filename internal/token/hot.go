@@ -0,0 +1,47 @@
+package token
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// maxHotCacheTTL HotCacheTTL 的上限：进程内副本不感知其他实例的刷新，保留时间应很短
+const maxHotCacheTTL = time.Minute
+
+// hotEntry 进程内副本及其失效时间
+type hotEntry struct {
+	token *TokenInfo
+	until time.Time
+}
+
+// hotCache 进程内已解析 token 的短期副本（HotCacheTTL）
+// 命中时既不访问共享缓存（Redis 往返），也不再反序列化；ttl 为 0 时关闭
+type hotCache struct {
+	ttl   time.Duration
+	entry atomic.Pointer[hotEntry]
+}
+
+// get 返回 now 时仍在保留期内的副本
+func (h *hotCache) get(now time.Time) *TokenInfo {
+	if h.ttl <= 0 {
+		return nil
+	}
+	e := h.entry.Load()
+	if e == nil || !now.Before(e.until) {
+		return nil
+	}
+	return e.token
+}
+
+// set 保存副本，保留到 now+ttl
+func (h *hotCache) set(token *TokenInfo, now time.Time) {
+	if h.ttl <= 0 || token == nil {
+		return
+	}
+	h.entry.Store(&hotEntry{token: token, until: now.Add(h.ttl)})
+}
+
+// clear 丢弃副本；作废、强制刷新、轮换 secret 时调用，使下次读取回到共享缓存
+func (h *hotCache) clear() {
+	h.entry.Store(nil)
+}
@@ -0,0 +1,56 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingDeleteCache Delete 始终失败的缓存，其余操作委托给 MemoryCache
+type failingDeleteCache struct {
+	Cache
+	err error
+}
+
+func (c *failingDeleteCache) Delete(context.Context, string) error {
+	return c.err
+}
+
+func TestDeleteFailure(t *testing.T) {
+	errDelete := errors.New("delete unavailable")
+	newManager := func(t *testing.T) (*Manager, *stubFetcher) {
+		t.Helper()
+		fetcher := &stubFetcher{}
+		m, err := newTestManager(&Config{Cache: &failingDeleteCache{Cache: NewMemoryCache(), err: errDelete}}, fetcher)
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		t.Cleanup(func() { m.Close() })
+		if _, err := m.GetToken(context.Background()); err != nil {
+			t.Fatalf("GetToken: %v", err)
+		}
+		return m, fetcher
+	}
+
+	t.Run("RotateSecret still refreshes", func(t *testing.T) {
+		m, fetcher := newManager(t)
+		code, err := m.RotateSecret(context.Background(), "secret-0002")
+		if code != CodeCacheDelete || !errors.Is(err, errDelete) {
+			t.Fatalf("RotateSecret: code=%v err=%v, want CodeCacheDelete wrapping the delete error", code, err)
+		}
+		if got := fetcher.calls.Load(); got != 2 {
+			t.Fatalf("fetch calls = %d, want 2", got)
+		}
+		tk, _, err := m.GetAccessToken(context.Background())
+		if err != nil || tk != "token-2" {
+			t.Fatalf("token after rotation = %q, %v, want token-2", tk, err)
+		}
+	})
+
+	t.Run("Invalidate returns the error", func(t *testing.T) {
+		m, _ := newManager(t)
+		if err := m.Invalidate(context.Background()); !errors.Is(err, errDelete) {
+			t.Fatalf("Invalidate = %v, want the delete error", err)
+		}
+	})
+}
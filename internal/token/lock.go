@@ -22,3 +22,25 @@ type TokenLocker interface {
 	// Lock 获取锁，返回解锁函数
 	Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error)
 }
+
+// lockStrategyKey ctx 中单次调用分布式锁策略的 key
+type lockStrategyKey struct{}
+
+// WithLockStrategy 返回携带分布式锁策略的 ctx，Manager 仅对这一次调用使用该策略
+// DistLockOff 跳过分布式锁（含 Redis 快速路径的占锁），只用本地互斥，适合预热、健康检查等不应争抢锁的路径
+// DistLockOn 要求本次调用持有分布式锁，Manager 未启用分布式锁时返回 ErrLockBackendMissing
+// DistLockAuto 及其他取值沿用配置的策略
+func WithLockStrategy(ctx context.Context, strategy DistLockStrategy) context.Context {
+	return context.WithValue(ctx, lockStrategyKey{}, strategy)
+}
+
+// lockStrategyFromContext 返回 ctx 中覆盖的策略；未设置或为 auto/未知取值时 ok 为 false
+func lockStrategyFromContext(ctx context.Context) (DistLockStrategy, bool) {
+	s, _ := ctx.Value(lockStrategyKey{}).(DistLockStrategy)
+	switch s {
+	case DistLockOn, DistLockOff:
+		return s, true
+	default:
+		return "", false
+	}
+}
@@ -19,27 +19,84 @@ const (
 	redisLockMaxRetry = 3
 	// redisLockJitterPercent 抖动百分比（0.2 表示 ±20%）
 	redisLockJitterPercent = 0.2
+	// maxInitialLockJitter 首次加锁前随机延迟的上限
+	maxInitialLockJitter = time.Second
 )
 
-func jitterInterval(base time.Duration) time.Duration {
-	if base <= 0 || redisLockJitterPercent <= 0 {
+// LockerOptions RedisLocker 的重试退避参数；零值字段使用默认值
+type LockerOptions struct {
+	// BaseInterval 首次退避间隔，之后按 2 倍递增；默认 250ms
+	BaseInterval time.Duration
+	// MaxInterval 退避间隔上限；默认 900ms
+	MaxInterval time.Duration
+	// MaxRetries 尝试获取锁的最大次数；默认 3
+	MaxRetries int
+	// JitterPercent 抖动比例（0.2 表示 ±20%），取值 [0,1)；默认 0.2，负数表示不抖动
+	JitterPercent float64
+	// InitialJitter 首次 SETNX 前的随机延迟上限，在 [0, InitialJitter) 内均匀取值，超过 1s 按 1s 处理；默认 0 不延迟
+	// 大量实例在 token 到期时同时抢锁时，可将首次尝试错开，避免各自的退避节奏对齐后反复碰撞；会给每次加锁增加平均 InitialJitter/2 的延迟
+	// 仅作用于常规加锁，RedisFastPath 的「读缓存 + 占锁」一次往返不受影响
+	InitialJitter time.Duration
+}
+
+// withDefaults 填充零值字段
+func (o LockerOptions) withDefaults() LockerOptions {
+	if o.BaseInterval <= 0 {
+		o.BaseInterval = redisLockRetryBaseInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = redisLockRetryMaxInterval
+	}
+	if o.MaxInterval < o.BaseInterval {
+		o.MaxInterval = o.BaseInterval
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = redisLockMaxRetry
+	}
+	switch {
+	case o.JitterPercent == 0:
+		o.JitterPercent = redisLockJitterPercent
+	case o.JitterPercent < 0:
+		o.JitterPercent = 0
+	case o.JitterPercent >= 1:
+		o.JitterPercent = 0.99
+	}
+	if o.InitialJitter < 0 {
+		o.InitialJitter = 0
+	}
+	if o.InitialJitter > maxInitialLockJitter {
+		o.InitialJitter = maxInitialLockJitter
+	}
+	return o
+}
+
+// initialDelay 首次加锁前的随机延迟，未开启 InitialJitter 时为 0
+func (o LockerOptions) initialDelay() time.Duration {
+	if o.InitialJitter <= 0 {
+		return 0
+	}
+	return randv2.N(o.InitialJitter)
+}
+
+func (o LockerOptions) jitterInterval(base time.Duration) time.Duration {
+	if base <= 0 || o.JitterPercent <= 0 {
 		return base
 	}
-	p := redisLockJitterPercent
+	p := o.JitterPercent
 	min := float64(base) * (1 - p)
 	max := float64(base) * (1 + p)
 	return time.Duration(min + randv2.Float64()*(max-min))
 }
 
-func backoffInterval(attempt int) time.Duration {
+func (o LockerOptions) backoffInterval(attempt int) time.Duration {
 	if attempt < 0 {
 		attempt = 0
 	}
-	interval := redisLockRetryBaseInterval * time.Duration(1<<attempt)
-	if interval > redisLockRetryMaxInterval {
-		interval = redisLockRetryMaxInterval
+	interval := o.BaseInterval << uint(attempt)
+	if interval <= 0 || interval > o.MaxInterval {
+		interval = o.MaxInterval
 	}
-	return jitterInterval(interval)
+	return o.jitterInterval(interval)
 }
 
 var unlockScript = redis.NewScript(`
@@ -52,21 +109,36 @@ return 0
 // RedisLocker 基于 Redis/Redis 集群的分布式锁
 type RedisLocker struct {
 	client redis.Cmdable
+	opts   LockerOptions
 }
 
 // NewRedisLocker 创建基于 Redis 的锁实现；cmd 可为 *redis.Client 或 *redis.ClusterClient
 func NewRedisLocker(cmd redis.Cmdable) *RedisLocker {
+	return NewRedisLockerWithOptions(cmd, LockerOptions{})
+}
+
+// NewRedisLockerWithOptions 创建基于 Redis 的锁实现，并自定义重试退避参数
+func NewRedisLockerWithOptions(cmd redis.Cmdable, opts LockerOptions) *RedisLocker {
 	if cmd == nil {
 		return nil
 	}
-	return &RedisLocker{client: cmd}
+	return &RedisLocker{client: cmd, opts: opts.withDefaults()}
 }
 
 // Lock 获取锁，带有限次数重试
 func (r *RedisLocker) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
 	lockVal := randomLockValue()
 
-	for i := 0; i < redisLockMaxRetry; i++ {
+	// 开启 InitialJitter 时错开首次尝试，避免同时到期的实例同一时刻抢锁
+	if delay := r.opts.initialDelay(); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	for i := 0; i < r.opts.MaxRetries; i++ {
 		ok, err := r.client.SetNX(ctx, key, lockVal, ttl).Result()
 		if err != nil {
 			return nil, err
@@ -77,11 +149,11 @@ func (r *RedisLocker) Lock(ctx context.Context, key string, ttl time.Duration) (
 			}
 			return unlock, nil
 		}
-		if i == redisLockMaxRetry-1 {
+		if i == r.opts.MaxRetries-1 {
 			break
 		}
 		// 尊重调用方上下文，避免无意义等待；指数退避 + 抖动
-		wait := backoffInterval(i)
+		wait := r.opts.backoffInterval(i)
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
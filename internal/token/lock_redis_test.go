@@ -0,0 +1,65 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestLockerBackoffGrowsAndIsCapped(t *testing.T) {
+	opts := LockerOptions{BaseInterval: 10 * time.Millisecond, MaxInterval: 100 * time.Millisecond, JitterPercent: -1}.withDefaults()
+	want := []time.Duration{10, 20, 40, 80, 100, 100}
+	for attempt, w := range want {
+		if got := opts.backoffInterval(attempt); got != w*time.Millisecond {
+			t.Fatalf("backoffInterval(%d) = %s, want %s", attempt, got, w*time.Millisecond)
+		}
+	}
+	// 位移溢出时仍取上限
+	if got := opts.backoffInterval(80); got != opts.MaxInterval {
+		t.Fatalf("backoffInterval(80) = %s, want %s", got, opts.MaxInterval)
+	}
+
+	jittered := LockerOptions{BaseInterval: 10 * time.Millisecond, MaxInterval: 100 * time.Millisecond, JitterPercent: 0.2}.withDefaults()
+	for i := 0; i < 200; i++ {
+		for attempt, w := range want {
+			base := w * time.Millisecond
+			got := jittered.backoffInterval(attempt)
+			if got < base*8/10 || got > base*12/10 {
+				t.Fatalf("jittered backoffInterval(%d) = %s, want within ±20%% of %s", attempt, got, base)
+			}
+		}
+	}
+}
+
+func TestLockerOptionsDefaults(t *testing.T) {
+	got := LockerOptions{}.withDefaults()
+	if got.BaseInterval != redisLockRetryBaseInterval || got.MaxInterval != redisLockRetryMaxInterval ||
+		got.MaxRetries != redisLockMaxRetry || got.JitterPercent != redisLockJitterPercent {
+		t.Fatalf("defaults = %+v", got)
+	}
+	if got := (LockerOptions{BaseInterval: time.Second, MaxInterval: time.Millisecond}).withDefaults(); got.MaxInterval != time.Second {
+		t.Fatalf("MaxInterval below BaseInterval = %s, want raised to %s", got.MaxInterval, time.Second)
+	}
+}
+
+func TestRedisLockerStopsAfterMaxRetries(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	if err := mr.Set("lock", "held"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	locker := NewRedisLockerWithOptions(client, LockerOptions{BaseInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, MaxRetries: 4})
+	start := mr.CommandCount()
+	if _, err := locker.Lock(context.Background(), "lock", time.Second); !errors.Is(err, ErrLockAcquire) {
+		t.Fatalf("Lock = %v, want ErrLockAcquire", err)
+	}
+	if got := mr.CommandCount() - start; got != 4 {
+		t.Fatalf("SETNX attempts = %d, want 4", got)
+	}
+}
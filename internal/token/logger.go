@@ -0,0 +1,14 @@
+package token
+
+// Logger 日志接口；*log.Logger 即满足该接口
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// logf 输出日志；未配置 Logger 时静默
+func (m *Manager) logf(format string, args ...any) {
+	if m.config.Logger == nil {
+		return
+	}
+	m.config.Logger.Printf("wxgo: "+format, args...)
+}
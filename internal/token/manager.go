@@ -2,13 +2,14 @@ package token
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -17,6 +18,11 @@ const (
 
 	// defaultLockTTL 分布式锁的默认租约时间（覆盖一次微信请求的耗时）
 	defaultLockTTL = 15 * time.Second
+	// lockWaitPollInterval 取锁失败后轮询缓存的间隔
+	lockWaitPollInterval = 100 * time.Millisecond
+
+	// defaultKeyPrefix 缓存/锁 key 的默认前缀
+	defaultKeyPrefix = "wxgo"
 )
 
 type cacheKind string
@@ -32,13 +38,31 @@ const (
 type Manager struct {
 	config     *Config
 	cache      Cache
-	httpClient *http.Client
+	httpClient *transport.Client
+	fetcher    TokenFetcher
 	mu         sync.Mutex // 保护并发获取 token（本地）
+	// secretMu 保护 config.AppSecret 的轮换写入，供不持有 mu 的读取方（如 EffectiveConfig）使用；写入时同时持有 mu
+	secretMu sync.RWMutex
 
 	distLocker   TokenLocker
+	claimer      *redisClaimer      // Redis 快速路径（RedisFastPath）；未开启时为 nil
+	refreshGroup singleflight.Group // 合并同进程内并发的缓存未命中
 	lockStrategy DistLockStrategy
 	lockTTL      time.Duration
 	selectedKind cacheKind
+
+	earlyRefresh time.Duration // 本实例的提前刷新窗口（含 TTLJitter 抖动）
+
+	hot        hotCache      // 进程内已解析 token 的短期副本（HotCacheTTL）
+	negative   negativeCache // 不可重试错误的短期负缓存
+	lastSecret string        // 最近一次使用的 AppSecret，用于识别 SecretProvider 轮换；受 mu 保护
+	metrics    Metrics
+	stats      stats
+	clock      Clock
+
+	bgCancel  context.CancelFunc // 取消后台刷新；未开启时为 nil
+	bgDone    chan struct{}      // 后台刷新协程退出时关闭
+	closeOnce sync.Once
 }
 
 // NewManager 创建 Token 管理器
@@ -48,21 +72,55 @@ func NewManager(config *Config) (*Manager, error) {
 	}
 
 	cacheImpl, cacheKind := resolveCache(config)
+	// FileCache 等自行计时的缓存与 Manager 使用同一时间源
+	if cc, ok := cacheImpl.(clockSetter); ok && config.Clock != nil {
+		cc.setClock(config.Clock)
+	}
 	strategy := config.lockStrategy()
 	locker, err := resolveLocker(config, cacheKind, cacheImpl, strategy)
 	if err != nil {
 		return nil, err
 	}
+	claimer, err := resolveClaimer(config, cacheKind, locker)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Manager{
+	m := &Manager{
 		config:       config,
 		cache:        cacheImpl,
-		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		httpClient:   newHTTPClient(config),
 		distLocker:   locker,
+		claimer:      claimer,
 		lockStrategy: strategy,
 		lockTTL:      defaultLockTTL,
 		selectedKind: cacheKind,
-	}, nil
+		clock:        config.clock(),
+		earlyRefresh: config.earlyRefresh(),
+	}
+	m.metrics = teeMetrics{stats: &m.stats, user: config.metrics()}
+	m.hot.ttl = config.HotCacheTTL
+	m.fetcher = config.Fetcher
+	if m.fetcher == nil {
+		m.fetcher = wechatFetcher{m: m}
+	}
+
+	if config.Fetcher == nil && !config.looksLikeAppID() {
+		m.logf("app_id %q does not start with wx or gh_, check that AppID and AppSecret are not swapped", config.AppID)
+	}
+	if config.BackgroundRefresh {
+		m.startRefresher()
+	}
+
+	return m, nil
+}
+
+// newHTTPClient 返回获取 token 使用的 HTTP 客户端，未配置时新建默认客户端
+func newHTTPClient(config *Config) *transport.Client {
+	if config.HTTPClient != nil {
+		return config.HTTPClient
+	}
+	return transport.NewClient()
 }
 
 // GetAccessToken 获取 Access Token
@@ -76,181 +134,786 @@ func NewManager(config *Config) (*Manager, error) {
 // 3) 锁获取失败不静默降级，返回 CodeLock 供上层决策
 // 4) 本地 mutex 仍保留，避免同进程重复刷新
 func (m *Manager) GetAccessToken(ctx context.Context) (string, Code, error) {
+	result, err := m.GetToken(ctx)
+	return result.AccessToken, result.Code, err
+}
+
+// GetToken 获取 Access Token 及其来源等详细信息，流程同 GetAccessToken
+// 返回值始终非 nil：失败时 AccessToken 为空、Code 为错误码；
+// CodeCacheSet 时 token 可用但写缓存失败，AccessToken 与 error 同时返回
+func (m *Manager) GetToken(ctx context.Context) (*TokenResult, error) {
+	// 进程内副本仍在保留期内时直接返回，不访问共享缓存，也无需派生带超时的 ctx
+	if token := m.hot.get(m.clock.Now()); token != nil && !m.expired(token) {
+		m.metrics.CacheHit()
+		return cachedResult(token), nil
+	}
+
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
 	cacheKey := m.getCacheKey()
 
 	// 先从缓存获取
 	token, err := m.cache.Get(ctx, cacheKey)
 	if err != nil {
-		return "", CodeCacheGet, fmt.Errorf("get token from cache: %w", err)
+		return failedResult(CodeCacheGet), fmt.Errorf("get token from cache: %w", err)
 	}
 
 	// 如果缓存存在且未过期，直接返回
-	if token != nil && !token.IsExpired() {
-		return token.AccessToken, CodeOK, nil
+	if token != nil && !m.expired(token) {
+		m.hot.set(token, m.clock.Now())
+		m.metrics.CacheHit()
+		return cachedResult(token), nil
 	}
+	m.metrics.CacheMiss()
+
+	// 近期因配置错误获取失败，直接返回同一错误，避免持续请求微信
+	// 配置了 SecretProvider 时交由 refresh 判断：secret 可能已轮换，需先清除负缓存
+	if m.config.SecretProvider == nil {
+		if code, err := m.negative.get(m.clock.Now()); err != nil {
+			return failedResult(code), err
+		}
+	}
+
+	return m.getTokenShared(ctx, cacheKey)
+}
 
-	// 需要刷新 token，使用 mutex 防止并发请求
+// getTokenShared 合并同进程内并发的未命中调用：同一时刻等待的协程共享一次加锁与获取的结果（成功或失败），
+// 避免获取失败时各协程依次抢锁、串行重试而成倍放大延迟
+// 共享调用使用发起者的 ctx；发起者放弃（ctx 取消/超时）而本调用 ctx 仍有效时，本调用自行重走一次流程
+func (m *Manager) getTokenShared(ctx context.Context, cacheKey string) (*TokenResult, error) {
+	key := cacheKey
+	if s, ok := lockStrategyFromContext(ctx); ok {
+		// 单次覆盖锁策略的调用不与其他策略的调用合并
+		key += "|" + string(s)
+	}
+	ch := m.refreshGroup.DoChan(key, func() (any, error) {
+		return m.getTokenLocked(ctx, cacheKey)
+	})
+
+	select {
+	case <-ctx.Done():
+		return failedResult(ContextCode(ctx.Err())), ctx.Err()
+	case r := <-ch:
+		if r.Err != nil && isContextErr(r.Err) && ctx.Err() == nil {
+			return m.getTokenLocked(ctx, cacheKey)
+		}
+		result := *r.Val.(*TokenResult)
+		return &result, r.Err
+	}
+}
+
+// getTokenLocked 在本地锁（及分布式锁）内再次检查缓存，仍未命中时刷新
+func (m *Manager) getTokenLocked(ctx context.Context, cacheKey string) (*TokenResult, error) {
+	// 需要刷新 token，使用 mutex 防止与 ForceRefresh/RotateSecret 等并发
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// 双重检查，可能其他 goroutine 已经刷新了
-	token, err = m.cache.Get(ctx, cacheKey)
+	// 等待 mutex 期间调用方可能已放弃，不再继续读缓存和请求微信
+	if err := ctx.Err(); err != nil {
+		return failedResult(ContextCode(err)), err
+	}
+
+	// 双重检查，可能其他 goroutine 已经刷新了；开启 Redis 快速路径时，未命中会在同一次往返内原子占锁
+	token, unlock, err := m.getOrClaim(ctx, cacheKey)
 	if err != nil {
-		return "", CodeCacheGet, fmt.Errorf("get token from cache: %w", err)
+		return failedResult(CodeCacheGet), fmt.Errorf("get token from cache: %w", err)
 	}
-	if token != nil && !token.IsExpired() {
-		return token.AccessToken, CodeOK, nil
+	if unlock != nil {
+		// 快速路径已占到锁，且占锁时缓存中没有 token，无需再检查
+		defer unlock()
+	} else {
+		if token != nil && !m.expired(token) {
+			return cachedResult(token), nil
+		}
+
+		// 如果需要分布式互斥，先取锁
+		unlock, err := m.acquireDistLock(ctx)
+		if err != nil {
+			// 锁被其他实例持有时，对方通常即将写入新 token，按配置等待其写入
+			if token := m.waitForToken(ctx, cacheKey, "", err); token != nil {
+				return cachedResult(token), nil
+			}
+			token, local := m.onLockFailure(ctx, cacheKey, err)
+			if token != nil {
+				return cachedResult(token), nil
+			}
+			if !local {
+				return failedResult(CodeLock), err
+			}
+		}
+		if unlock != nil {
+			defer unlock()
+		}
+		if err := ctx.Err(); err != nil {
+			return failedResult(ContextCode(err)), err
+		}
+
+		// 锁内再检查一次，避免其他实例已写入；读主库，不受只读副本复制延迟影响
+		token, err = m.getPrimary(ctx, cacheKey)
+		if err != nil {
+			return failedResult(CodeCacheGet), fmt.Errorf("get token from cache: %w", err)
+		}
+		if token != nil && !m.expired(token) {
+			return cachedResult(token), nil
+		}
+	}
+
+	result, err := m.refresh(ctx, cacheKey)
+	if err != nil && token != nil && m.config.StaleIfError && m.staleUsable(token) && staleEligible(result.Code, err) {
+		// 微信不可达时返回缓存中已过期的 token 兜底；微信通常在 expires_in 之后仍短暂接受旧 token
+		m.logf("fetch token failed, serving stale token expired at %s: %v", token.ExpiresAt.Format(time.RFC3339), err)
+		return staleResult(token), nil
+	}
+	return result, err
+}
+
+// ForceRefresh 跳过缓存有效期判断，强制从微信刷新 Access Token 并写入缓存
+// invalid 非空时表示调用方确认该 token 已被微信判定失效：
+// 若缓存中已是另一个未过期的 token（其他协程/实例已刷新），直接返回它，避免重复刷新；
+// 若缓存中仍是该失效 token，则在分布式锁内先删除再刷新，使其他实例同样重新获取
+func (m *Manager) ForceRefresh(ctx context.Context, invalid string) (string, Code, error) {
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
+	cacheKey := m.getCacheKey()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", ContextCode(err), err
 	}
 
-	// 如果需要分布式互斥，先取锁
 	unlock, err := m.acquireDistLock(ctx)
 	if err != nil {
+		if invalid != "" {
+			if token := m.waitForToken(ctx, cacheKey, invalid, err); token != nil {
+				return token.AccessToken, CodeOK, nil
+			}
+		}
 		return "", CodeLock, err
 	}
 	if unlock != nil {
 		defer unlock()
 	}
+	if err := ctx.Err(); err != nil {
+		return "", ContextCode(err), err
+	}
+
+	m.hot.clear()
+	if invalid != "" {
+		token, err := m.getPrimary(ctx, cacheKey)
+		if err != nil {
+			return "", CodeCacheGet, fmt.Errorf("get token from cache: %w", err)
+		}
+		if token != nil && token.AccessToken != invalid && !m.expired(token) {
+			return token.AccessToken, CodeOK, nil
+		}
+		// 先删除共享缓存中的失效 token，即便随后刷新失败，其他实例也不会继续使用它
+		// 全程持有分布式锁：其他实例读到空缓存后会等锁，拿到锁时读到的是本次写入的新 token
+		if token != nil && token.AccessToken == invalid {
+			if err := m.cache.Delete(ctx, cacheKey); err != nil {
+				m.logf("delete invalid token from cache: %v", err)
+			}
+		}
+	}
+
+	if m.config.provider() == ProviderStable && m.config.Fetcher == nil {
+		result, err := m.refreshStable(ctx, cacheKey)
+		return result.AccessToken, result.Code, err
+	}
+	result, err := m.refresh(ctx, cacheKey)
+	return result.AccessToken, result.Code, err
+}
+
+// RotateSecret 在本地锁与分布式锁内替换 AppSecret，删除缓存中的旧 token、清除负缓存，并立即用新 secret 刷新
+// 配置了 SecretProvider 时 newSecret 须为空（secret 由 provider 提供），此时仅作废旧 token 并刷新
+// 多实例同时轮换时由分布式锁串行执行；拿到锁时缓存中的 token 若由其他 RotateSecret 在本次轮换开始后获取（Rotated 且 FetchedAt 更晚），
+// 说明轮换已完成，直接沿用该 token，不再重复请求微信（依赖各实例时钟基本同步）
+// 删除旧 token 为尽力而为：失败时仍继续刷新（新 token 会覆盖旧值），刷新成功后返回 CodeCacheDelete 与删除错误，轮换本身已完成
+func (m *Manager) RotateSecret(ctx context.Context, newSecret string) (Code, error) {
+	newSecret = strings.TrimSpace(newSecret)
+	if m.config.SecretProvider != nil {
+		if newSecret != "" {
+			return CodeInvalidParam, fmt.Errorf("secret provider is configured, rotate the secret through it")
+		}
+	} else {
+		if newSecret == "" {
+			return CodeMissingAppSecret, ErrMissingAppSecret
+		}
+		if hasInvalidChar(newSecret) {
+			return CodeInvalidParam, ErrInvalidAppSecret
+		}
+	}
+
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
+	cacheKey := m.getCacheKey()
+	begun := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.acquireDistLock(ctx)
+	if err != nil {
+		return CodeLock, err
+	}
+	if unlock != nil {
+		defer unlock()
+	}
+
+	if newSecret != "" {
+		m.secretMu.Lock()
+		m.config.AppSecret = newSecret
+		m.secretMu.Unlock()
+	}
+	m.negative.clear()
+	m.hot.clear()
+
+	// 等锁期间其他实例已完成轮换；普通刷新写入的 token 可能仍用旧 secret 获取，不能沿用
+	if token, err := m.cache.Get(ctx, cacheKey); err == nil && token != nil && token.Rotated && token.FetchedAt.After(begun) && !m.expired(token) {
+		m.hot.set(token, m.clock.Now())
+		return CodeOK, nil
+	}
+	delErr := m.cache.Delete(ctx, cacheKey)
+	if delErr != nil {
+		m.logf("delete token from cache before refresh: %v", delErr)
+	}
 
-	// 锁内再检查一次，避免其他实例已写入
-	token, err = m.cache.Get(ctx, cacheKey)
+	result, err := m.refresh(withRotation(ctx), cacheKey)
 	if err != nil {
-		return "", CodeCacheGet, fmt.Errorf("get token from cache: %w", err)
+		return result.Code, err
 	}
-	if token != nil && !token.IsExpired() {
-		return token.AccessToken, CodeOK, nil
+	if delErr != nil {
+		return CodeCacheDelete, fmt.Errorf("delete token from cache: %w", delErr)
 	}
+	return result.Code, nil
+}
+
+// rotationKey ctx 中「本次获取由 RotateSecret 发起」标记的 key
+type rotationKey struct{}
+
+// withRotation 标记本次获取由 RotateSecret 发起
+func withRotation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rotationKey{}, true)
+}
 
-	// 从微信 API 获取新 token
-	newToken, code, err := m.fetchTokenFromWeChat(ctx)
+// rotationFromContext 本次获取是否由 RotateSecret 发起
+func rotationFromContext(ctx context.Context) bool {
+	rotated, _ := ctx.Value(rotationKey{}).(bool)
+	return rotated
+}
+
+// refresh 从微信获取新 token 并写入缓存；调用方需持有本地锁（及分布式锁）
+func (m *Manager) refresh(ctx context.Context, cacheKey string) (*TokenResult, error) {
+	if m.config.Fetcher == nil {
+		secret, err := m.config.secret(ctx)
+		if err != nil {
+			return failedResult(CodeMissingAppSecret), err
+		}
+		// secret 已轮换：之前因旧 secret 记录的负缓存不再适用
+		if m.lastSecret != "" && secret != m.lastSecret {
+			m.negative.clear()
+		}
+		m.lastSecret = secret
+	}
+
+	ttl := m.config.negativeCacheTTL()
+	if ttl > 0 {
+		if code, err := m.negative.get(m.clock.Now()); err != nil {
+			return failedResult(code), err
+		}
+	}
+
+	// 获取新 token（默认请求微信，可由 Config.Fetcher 替换）
+	start := time.Now()
+	newToken, code, err := m.fetch(ctx)
+	m.metrics.TokenFetch(code, time.Since(start))
+	m.stats.recordFetch(m.clock.Now(), err)
 	if err != nil {
-		return "", code, err
+		if ttl > 0 {
+			m.negative.record(code, err, m.clock.Now().Add(ttl))
+		}
+		return failedResult(code), err
+	}
+
+	newToken.FetchedAt = m.clock.Now()
+	newToken.Rotated = rotationFromContext(ctx)
+	result := &TokenResult{
+		AccessToken: newToken.AccessToken,
+		ExpiresAt:   newToken.ExpiresAt,
+		Code:        CodeOK,
+		expiresIn:   newToken.ExpiresIn,
 	}
 
 	// 保存到缓存
-	ttl := time.Duration(newToken.ExpiresIn) * time.Second
-	if err := m.cache.Set(ctx, cacheKey, newToken, ttl); err != nil {
+	m.hot.set(newToken, m.clock.Now())
+	if err := m.cache.Set(ctx, cacheKey, newToken, m.config.cacheTTL(newToken.ExpiresIn)); err != nil {
 		// 返回缓存写入错误，便于上层观测；token 仍返回供调用方兜底使用
-		return newToken.AccessToken, CodeCacheSet, fmt.Errorf("set token to cache: %w", err)
+		result.Code = CodeCacheSet
+		return result, fmt.Errorf("set token to cache: %w", err)
 	}
 
-	return newToken.AccessToken, CodeOK, nil
+	return result, nil
+}
+
+// Stats 返回运行时累计计数快照
+func (m *Manager) Stats() Stats {
+	return m.stats.snapshot()
 }
 
-// fetchTokenFromWeChat 从微信 API 获取 Token
-func (m *Manager) fetchTokenFromWeChat(ctx context.Context) (*TokenInfo, Code, error) {
-	params := url.Values{}
-	params.Set("grant_type", "client_credential")
-	params.Set("appid", m.config.AppID)
-	params.Set("secret", m.config.AppSecret)
+// Invalidate 删除缓存中的 token，不触发刷新；下次 GetAccessToken 将重新获取
+// 删除失败时返回错误：旧 token 仍留在缓存中，其他实例会继续使用，调用方应视为失败并重试
+func (m *Manager) Invalidate(ctx context.Context) error {
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
 
-	reqURL := WeChatTokenAPI + "?" + params.Encode()
+	m.hot.clear()
+	if err := m.cache.Delete(ctx, m.getCacheKey()); err != nil {
+		return fmt.Errorf("delete token from cache: %w", err)
+	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+// Flush 删除缓存中当前 KeyPrefix 下所有 AppID 的 token（不含锁 key）
+// 缓存未实现 Flusher 时返回 ErrFlushNotSupported
+func (m *Manager) Flush(ctx context.Context) error {
+	flusher, ok := m.cache.(Flusher)
+	if !ok {
+		return ErrFlushNotSupported
+	}
+
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
+	// 缓存 key 形如 {prefix}:token:{appID}，去掉 appID 即为同类 token 的公共前缀
+	m.hot.clear()
+	parts := m.config.keyParts()
+	prefix := strings.TrimSuffix(parts.CacheKey(), parts.id())
+	if err := flusher.Flush(ctx, prefix); err != nil {
+		return fmt.Errorf("flush %s cache: %w", m.selectedKind, err)
+	}
+	return nil
+}
+
+// PingCache 探测缓存后端是否可达；缓存未实现 Pinger 时视为可用
+func (m *Manager) PingCache(ctx context.Context) error {
+	pinger, ok := m.cache.(Pinger)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
+	if err := pinger.Ping(ctx); err != nil {
+		return fmt.Errorf("ping %s cache: %w", m.selectedKind, err)
+	}
+	return nil
+}
+
+// CachedToken 只读缓存中的 token，不触发微信请求
+// 缓存中不存在时返回 CodeNotCached 与 ErrTokenNotCached；已过期的 token 同样返回，由调用方判断
+func (m *Manager) CachedToken(ctx context.Context) (*TokenInfo, Code, error) {
+	ctx, cancel := transport.WithDefaultTimeout(ctx, m.config.DefaultTimeout)
+	defer cancel()
+
+	token, err := m.cache.Get(ctx, m.getCacheKey())
 	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("create request: %w", err)
+		return nil, CodeCacheGet, fmt.Errorf("get token from cache: %w", err)
+	}
+	if token == nil {
+		return nil, CodeNotCached, ErrTokenNotCached
+	}
+	return token, CodeOK, nil
+}
+
+// fetchTokenFromWeChat 使用指定 secret 从微信 API 获取 Token，地址与参数由 Provider 决定
+func (m *Manager) fetchTokenFromWeChat(ctx context.Context, secret string) (*TokenInfo, Code, error) {
+	var ticket string
+	if m.config.provider() == ProviderComponent {
+		t, err := m.config.verifyTicket(ctx)
+		if err != nil {
+			return nil, CodeMissingVerifyTicket, err
+		}
+		ticket = t
+	}
+
+	req, err := m.config.newTokenRequest(ctx, secret, ticket)
+	if err != nil {
+		return nil, CodeHTTP, fmt.Errorf("create request: %w", transport.RedactURLError(err))
 	}
 
-	resp, err := m.httpClient.Do(req)
+	// transport.Client 已将错误中的 URL 去掉 query（含 secret）
+	resp, err := m.httpClient.Do(ctx, req)
 	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("request wechat api: %w", err)
+		return nil, RequestErrorCode(ctx, err), fmt.Errorf("request wechat api: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, CodeHTTP, fmt.Errorf("wechat api status: %d", resp.StatusCode)
+		m.httpClient.Tap(req, resp.StatusCode, nil)
+		return nil, CodeHTTP, &transport.StatusError{Target: "token api", StatusCode: resp.StatusCode}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := transport.ReadBody(resp.Body, m.config.MaxResponseBytes)
 	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("read response: %w", err)
+		return nil, ReadErrorCode(ctx, err, CodeHTTP), fmt.Errorf("read response: %w", err)
 	}
+	m.httpClient.Tap(req, resp.StatusCode, body)
 
 	var apiResp struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-		ErrCode     int    `json:"errcode"`
-		ErrMsg      string `json:"errmsg"`
+		AccessToken          string `json:"access_token"`
+		ComponentAccessToken string `json:"component_access_token"`
+		ExpiresIn            int    `json:"expires_in"`
 	}
-
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, CodeInvalidResponse, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	if code, err := DecodeWeChatResponse(body, &apiResp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			// errmsg 可能回显请求参数，其中的 secret 不能进入错误与日志
+			if secret != "" {
+				apiErr.ErrMsg = strings.ReplaceAll(apiErr.ErrMsg, secret, "****")
+			}
+			if apiErr.ErrCode == errCodeIPNotWhitelisted {
+				return nil, CodeIPNotWhitelisted, ipWhitelistError(apiErr)
+			}
+		}
+		return nil, code, err
 	}
 
-	// 检查微信 API 错误
-	if apiResp.ErrCode != 0 {
-		return nil, CodeAPIError, fmt.Errorf("%w: errcode=%d, errmsg=%s", ErrAPIError, apiResp.ErrCode, apiResp.ErrMsg)
+	// 第三方平台返回 component_access_token，其余字段一致
+	if apiResp.AccessToken == "" {
+		apiResp.AccessToken = apiResp.ComponentAccessToken
 	}
-
 	if apiResp.AccessToken == "" {
 		return nil, CodeInvalidResponse, ErrInvalidResponse
 	}
+	// expires_in 缺失或非正数时无法计算缓存 TTL，写入 Redis 会变成永不过期的 key
+	if apiResp.ExpiresIn <= 0 {
+		return nil, CodeInvalidResponse, fmt.Errorf("%w: expires_in=%d", ErrInvalidResponse, apiResp.ExpiresIn)
+	}
 
 	// 计算实际过期时间
 	tokenInfo := &TokenInfo{
 		AccessToken: apiResp.AccessToken,
 		ExpiresIn:   apiResp.ExpiresIn,
-		ExpiresAt:   time.Now().Add(time.Duration(apiResp.ExpiresIn) * time.Second),
+		ExpiresAt:   m.clock.Now().Add(time.Duration(apiResp.ExpiresIn) * time.Second),
 	}
 
 	return tokenInfo, CodeOK, nil
 }
 
-// getCacheKey 获取缓存 key
+// getCacheKey 获取缓存 key：{prefix}:{credential}:{appID}，凭据名见 Config.Credential
 func (m *Manager) getCacheKey() string {
-	return fmt.Sprintf("wxgo:token:%s", m.config.AppID)
+	return m.config.keyParts().CacheKey()
 }
 
-// getLockKey 获取分布式锁 key
+// getLockKey 获取分布式锁 key：{prefix}:{credential}_lock:{appID}
 func (m *Manager) getLockKey() string {
-	return fmt.Sprintf("wxgo:token_lock:%s", m.config.AppID)
+	return m.config.keyParts().LockKey()
+}
+
+// KeyParts 缓存/锁 key 的组成部分；Manager 内部与 CacheKey、LockKey 均经它生成 key，格式只在此处定义
+type KeyParts struct {
+	// Prefix key 前缀（Config.KeyPrefix），为空时使用默认前缀 wxgo
+	Prefix string
+	// Credential 凭据名（Config.Credential），为空时为 token
+	Credential string
+	// AppID 公众号 AppID 或企业微信 corpid
+	AppID string
+	// Scope 同一 AppID 下区分应用的后缀：企业微信为 AgentID，未设置 AgentID 时为 SecretScope(AppSecret)；其余平台为空
+	Scope string
+	// HashTag 是否以 Redis Cluster hash tag 包裹 AppID 与 Scope（Config.KeyHashTag）
+	HashTag bool
+}
+
+// CacheKey 返回缓存 key：{prefix}:{credential}:{appID[:scope]}
+func (k KeyParts) CacheKey() string {
+	return k.format(k.credential())
+}
+
+// LockKey 返回分布式锁 key：{prefix}:{credential}_lock:{appID[:scope]}
+func (k KeyParts) LockKey() string {
+	return k.format(k.credential() + "_lock")
+}
+
+func (k KeyParts) credential() string {
+	if k.Credential == "" {
+		return "token"
+	}
+	return k.Credential
+}
+
+// format 返回 {prefix}:{kind}:{id}
+func (k KeyParts) format(kind string) string {
+	return fmt.Sprintf("%s:%s:%s", effectiveKeyPrefix(k.Prefix), kind, k.id())
+}
+
+// id 返回 key 中标识应用的部分：appID 或 appID:scope，开启 HashTag 时以 {} 包裹
+// hash tag 放在 key 末尾而非紧随前缀，使同类 key 仍共享 {prefix}:{credential}: 前缀，按前缀清理不受影响
+func (k KeyParts) id() string {
+	id := k.AppID
+	if k.Scope != "" {
+		id += ":" + k.Scope
+	}
+	if k.HashTag {
+		return "{" + id + "}"
+	}
+	return id
+}
+
+// CacheKey 返回 token 的缓存 key：{prefix}:token:{appID}；prefix 为空时使用默认前缀 wxgo
+func CacheKey(appID, prefix string) string {
+	return KeyParts{Prefix: prefix, AppID: appID}.CacheKey()
+}
+
+// LockKey 返回刷新 token 使用的分布式锁 key：{prefix}:token_lock:{appID}；prefix 为空时使用默认前缀 wxgo
+func LockKey(appID, prefix string) string {
+	return KeyParts{Prefix: prefix, AppID: appID}.LockKey()
 }
 
 func (m *Manager) acquireDistLock(ctx context.Context) (func() error, error) {
-	if m.distLocker == nil {
-		return nil, nil
+	locker, err := m.distLockerFor(ctx)
+	if err != nil || locker == nil {
+		return nil, err
 	}
-	unlock, err := m.distLocker.Lock(ctx, m.getLockKey(), m.lockTTL)
+	start := time.Now()
+	unlock, err := locker.Lock(ctx, m.getLockKey(), m.lockTTL)
+	m.metrics.LockAcquire(time.Since(start), err)
 	if err != nil {
 		return nil, err
 	}
-	return unlock, nil
+	return m.trackLock(unlock), nil
+}
+
+// distLockerFor 返回本次调用使用的分布式锁，ctx 可通过 WithLockStrategy 覆盖配置的策略
+func (m *Manager) distLockerFor(ctx context.Context) (TokenLocker, error) {
+	strategy, ok := lockStrategyFromContext(ctx)
+	if !ok {
+		return m.distLocker, nil
+	}
+	switch strategy {
+	case DistLockOff:
+		return nil, nil
+	default:
+		if m.distLocker == nil {
+			return nil, fmt.Errorf("%w: per-call strategy %q", ErrLockBackendMissing, strategy)
+		}
+		return m.distLocker, nil
+	}
+}
+
+// getOrClaim 读取缓存中的 token；开启 Redis 快速路径时，未命中则原子占用分布式锁并返回解锁函数
+// 未占到锁（未开启快速路径、命中或锁被他人持有）时 unlock 为 nil，由调用方走常规加锁流程
+func (m *Manager) getOrClaim(ctx context.Context, cacheKey string) (*TokenInfo, func() error, error) {
+	if s, ok := lockStrategyFromContext(ctx); m.claimer == nil || (ok && s == DistLockOff) {
+		token, err := m.cache.Get(ctx, cacheKey)
+		return token, nil, err
+	}
+
+	start := time.Now()
+	token, unlock, err := m.claimer.getOrClaim(ctx, cacheKey, m.getLockKey(), m.lockTTL)
+	if err != nil || unlock == nil {
+		return token, nil, err
+	}
+	m.metrics.LockAcquire(time.Since(start), nil)
+	return nil, m.trackLock(unlock), nil
+}
+
+// trackLock 包装解锁函数，记录持锁时长：超过 lockTTL 时锁已自动过期，其他实例可能已并发刷新
+func (m *Manager) trackLock(unlock func() error) func() error {
+	acquired := time.Now()
+	return func() error {
+		held := time.Since(acquired)
+		err := unlock()
+		overrun := held > m.lockTTL
+		m.observeLockHeld(held, overrun)
+		if !overrun {
+			return err
+		}
+		m.logf("distributed lock held for %s, longer than its ttl %s; another instance may have refreshed concurrently", held, m.lockTTL)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: held %s, ttl %s", ErrLockOverrun, held, m.lockTTL)
+	}
+}
+
+// observeLockHeld 上报持锁时长：计入 Stats，用户 Metrics 实现 LockHoldObserver 时一并通知
+func (m *Manager) observeLockHeld(held time.Duration, overrun bool) {
+	if overrun {
+		m.stats.lockOverruns.Add(1)
+	}
+	if o, ok := m.config.Metrics.(LockHoldObserver); ok {
+		o.LockHeld(held, overrun)
+	}
+}
+
+// expired 按本实例的提前刷新窗口判断 token 是否需要刷新
+func (m *Manager) expired(token *TokenInfo) bool {
+	return token.ExpiredWithin(m.clock.Now(), m.earlyRefresh)
+}
+
+// Expired 按本实例的时钟与提前刷新窗口（EarlyRefresh 及 TTLJitter）判断 token 是否已过期，与 GetAccessToken 的判断一致
+func (m *Manager) Expired(token *TokenInfo) bool {
+	return m.expired(token)
+}
+
+// staleUsable 过期 token 是否仍在 stale 窗口内；MemoryCache 等不淘汰条目的缓存也不会返回更早过期的 token
+func (m *Manager) staleUsable(token *TokenInfo) bool {
+	return !m.clock.Now().After(token.ExpiresAt.Add(m.config.staleWindow()))
+}
+
+// staleEligible 获取失败是否属于网络/微信侧故障，可用过期 token 兜底
+// 微信明确返回的业务错误（如 secret 错误）不兜底，仅可重试的错误码（如系统繁忙 -1）除外
+func staleEligible(code Code, err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryable(apiErr.ErrCode, 0)
+	}
+	switch code {
+	case CodeHTTP, CodeNonJSONResponse, CodeInvalidResponse:
+		return true
+	}
+	return false
+}
+
+// waitForToken 取锁失败（ErrLockAcquire）后在 LockWaitTimeout 内轮询缓存，等待持锁实例写入新 token
+// 读到未过期且不等于 reject 的 token 时返回它；超时、ctx 结束或未开启时返回 nil
+func (m *Manager) waitForToken(ctx context.Context, cacheKey, reject string, lockErr error) *TokenInfo {
+	if m.config.LockWaitTimeout <= 0 || !errors.Is(lockErr, ErrLockAcquire) {
+		return nil
+	}
+
+	ticker := time.NewTicker(lockWaitPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(m.config.LockWaitTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timeout.C:
+			return nil
+		case <-ticker.C:
+		}
+
+		token, err := m.cache.Get(ctx, cacheKey)
+		if err != nil {
+			continue
+		}
+		if token != nil && token.AccessToken != reject && !m.expired(token) {
+			return token
+		}
+	}
+}
+
+// onLockFailure auto 策略下取锁失败（如 Redis 抖动）时的降级：最后再读一次缓存，命中有效 token 则返回它；
+// 仍未命中且开启 AllowLocalRefreshOnLockFail 时 local 为 true，由调用方只在本地锁内刷新
+// on 策略（含 WithLockStrategy 单次覆盖）始终严格失败
+func (m *Manager) onLockFailure(ctx context.Context, cacheKey string, lockErr error) (token *TokenInfo, local bool) {
+	strategy := m.lockStrategy
+	if s, ok := lockStrategyFromContext(ctx); ok {
+		strategy = s
+	}
+	if strategy != DistLockAuto || ctx.Err() != nil {
+		return nil, false
+	}
+
+	token, err := m.getPrimary(ctx, cacheKey)
+	if err == nil && token != nil && !m.expired(token) {
+		return token, false
+	}
+	if !m.config.AllowLocalRefreshOnLockFail {
+		return nil, false
+	}
+	m.logf("acquire distributed lock failed, refreshing under the local lock only: %v", lockErr)
+	return nil, true
+}
+
+// getPrimary 从缓存主库读取：缓存读写分离（RedisReadClient）时绕过只读副本，否则等同 cache.Get
+func (m *Manager) getPrimary(ctx context.Context, key string) (*TokenInfo, error) {
+	if p, ok := m.cache.(primaryReader); ok {
+		return p.getPrimary(ctx, key)
+	}
+	return m.cache.Get(ctx, key)
 }
 
 // resolveCache 根据配置选择缓存实现（优先级：Cache > RedisCluster > Redis > 内存）
+// PreferRedis 为 single 时 Redis 单点优先于集群
 func resolveCache(c *Config) (Cache, cacheKind) {
 	if c.Cache != nil {
 		return c.Cache, cacheKindCustom
 	}
+	if c.PreferRedis == PreferRedisSingle && c.RedisClient != nil {
+		return NewRedisCacheWithReader(c.RedisClient, c.RedisReadClient), cacheKindRedis
+	}
 	if c.RedisClusterClient != nil {
 		return NewRedisClusterCache(c.RedisClusterClient), cacheKindRC
 	}
 	if c.RedisClient != nil {
-		return NewRedisCache(c.RedisClient), cacheKindRedis
+		return NewRedisCacheWithReader(c.RedisClient, c.RedisReadClient), cacheKindRedis
 	}
 	return NewMemoryCache(), cacheKindMemory
 }
 
+// lockBackendMissingError DistLockOn 下找不到锁后端时的诊断错误：逐项说明检查过的后端及其状态，并给出可行的配置方式
+// 仍包装 ErrLockBackendMissing，可用 errors.Is 判断
+func lockBackendMissingError(c *Config, kind cacheKind) error {
+	var checked []string
+	switch {
+	case c.Cache == nil:
+		checked = append(checked, "Cache: not set")
+	default:
+		checked = append(checked, fmt.Sprintf("Cache: %T is set but does not implement TokenLocker", c.Cache))
+	}
+	// 选用 Redis/集群缓存时必然复用其做锁，走到这里说明 Redis 客户端要么未设置、要么被自定义 Cache 覆盖
+	redisState := func(set bool) string {
+		if !set {
+			return "not set"
+		}
+		return "set but unused, Cache takes priority"
+	}
+	checked = append(checked,
+		"RedisClusterClient: "+redisState(c.RedisClusterClient != nil),
+		"RedisClient: "+redisState(c.RedisClient != nil),
+	)
+
+	return fmt.Errorf("%w: DistLockStrategy is %q but the selected cache (%s) provides no lock; checked LockRedisClient/LockRedisClusterClient: not set; %s; "+
+		"set LockRedisClient or LockRedisClusterClient (works with any Cache), set RedisClient or RedisClusterClient (without a custom Cache), "+
+		"pass a Cache that implements TokenLocker, or use DistLockAuto/DistLockOff for single-instance deployments",
+		ErrLockBackendMissing, DistLockOn, kind, strings.Join(checked, "; "))
+}
+
 // resolveLocker 根据策略与缓存类型选择分布式锁
 func resolveLocker(c *Config, kind cacheKind, cache Cache, strategy DistLockStrategy) (TokenLocker, error) {
 	switch strategy {
 	case DistLockOff:
 		return nil, nil
 	case DistLockAuto, DistLockOn:
-		// 1) 缓存自带 TokenLocker 优先
+		// 1) 显式配置的锁专用 Redis 客户端优先
+		if cmd := c.lockRedis(); cmd != nil {
+			return NewRedisLockerWithOptions(cmd, c.LockerOptions), nil
+		}
+
+		// 2) 缓存自带 TokenLocker
 		if locker, ok := cache.(TokenLocker); ok && locker != nil {
 			return locker, nil
 		}
 
-		// 2) 没有自带锁时，若使用 Redis/集群缓存则复用它做锁（集群优先）
+		// 3) 没有自带锁时，若使用 Redis/集群缓存则复用它做锁（集群优先）
 		if kind == cacheKindRC && c.RedisClusterClient != nil {
-			return NewRedisLocker(c.RedisClusterClient), nil
+			return NewRedisLockerWithOptions(c.RedisClusterClient, c.LockerOptions), nil
 		}
 		if kind == cacheKindRedis && c.RedisClient != nil {
-			return NewRedisLocker(c.RedisClient), nil
+			return NewRedisLockerWithOptions(c.RedisClient, c.LockerOptions), nil
 		}
 
 		if strategy == DistLockOn {
-			return nil, ErrLockBackendMissing
+			return nil, lockBackendMissingError(c, kind)
 		}
 		return nil, nil
 	default:
@@ -0,0 +1,52 @@
+package token
+
+import "time"
+
+// Metrics 指标回调接口，实现需并发安全
+// 可对接 Prometheus、StatsD 等任意指标系统
+type Metrics interface {
+	// CacheHit 缓存命中有效 token
+	CacheHit()
+	// CacheMiss 缓存未命中或 token 已过期
+	CacheMiss()
+	// TokenFetch 向微信获取 token 一次（无论成败），code 为结果码
+	TokenFetch(code Code, duration time.Duration)
+	// LockAcquire 获取分布式锁一次，err 非 nil 表示失败
+	LockAcquire(wait time.Duration, err error)
+}
+
+// LockHoldObserver 可选接口：Metrics 实现它即可观测分布式锁的持有时长
+// overrun 为 true 表示持锁时间超过锁 TTL，锁已在持有期间过期
+type LockHoldObserver interface {
+	LockHeld(held time.Duration, overrun bool)
+}
+
+// nopMetrics 未配置 Metrics 时使用的空实现
+type nopMetrics struct{}
+
+func (nopMetrics) CacheHit()                        {}
+func (nopMetrics) CacheMiss()                       {}
+func (nopMetrics) TokenFetch(Code, time.Duration)   {}
+func (nopMetrics) LockAcquire(time.Duration, error) {}
+
+// metrics 返回有效的 Metrics 实现
+func (c *Config) metrics() Metrics {
+	if c.Metrics == nil {
+		return nopMetrics{}
+	}
+	return c.Metrics
+}
+
+// CredentialMetrics 可选接口：Metrics 实现它即可按凭据名区分 jsapi_ticket 等附加凭据的指标
+type CredentialMetrics interface {
+	ForCredential(credential string) Metrics
+}
+
+// MetricsForCredential 返回 credential 专用的 Metrics：m 实现 CredentialMetrics 时取其 ForCredential，
+// 否则返回 nil（不上报），避免 ticket 的获取与缓存命中计入 access_token 指标
+func MetricsForCredential(m Metrics, credential string) Metrics {
+	if cm, ok := m.(CredentialMetrics); ok {
+		return cm.ForCredential(credential)
+	}
+	return nil
+}
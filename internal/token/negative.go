@@ -0,0 +1,53 @@
+package token
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultNegativeCacheTTL 开启负缓存但未指定时长时的默认值
+const defaultNegativeCacheTTL = 5 * time.Second
+
+// negativeCache 进程内缓存最近一次不可重试的获取失败，期间直接返回同一错误
+type negativeCache struct {
+	mu    sync.Mutex
+	code  Code
+	err   error
+	until time.Time
+}
+
+// get 返回 now 时刻仍在有效期内的失败结果；无缓存时 err 为 nil
+func (n *negativeCache) get(now time.Time) (Code, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.err == nil || now.After(n.until) {
+		return "", nil
+	}
+	return n.code, n.err
+}
+
+// record 若 err 为配置类微信错误（见 errCodeClasses）则缓存至 until；网络错误、5xx、限流等不缓存
+func (n *negativeCache) record(code Code, err error, until time.Time) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !isConfigErrCode(apiErr.ErrCode) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.code = code
+	n.err = err
+	n.until = until
+}
+
+// clear 清除负缓存
+func (n *negativeCache) clear() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.err = nil
+	n.until = time.Time{}
+}
@@ -0,0 +1,136 @@
+package token
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Provider token 接口的提供方，决定获取 token 的地址与参数
+type Provider string
+
+const (
+	// ProviderMP 公众号/小程序（默认）：/cgi-bin/token?grant_type=client_credential&appid=&secret=
+	ProviderMP Provider = "mp"
+	// ProviderWorkWeChat 企业微信：/cgi-bin/gettoken?corpid=&corpsecret=
+	ProviderWorkWeChat Provider = "work"
+	// ProviderComponent 开放平台第三方平台：POST /cgi-bin/component/api_component_token 获取 component_access_token
+	// AppID/AppSecret 为 component_appid/component_appsecret，component_verify_ticket 由 ComponentVerifyTicket 提供
+	ProviderComponent Provider = "component"
+	// ProviderStable 公众号/小程序稳定版接口：POST /cgi-bin/stable_token，与普通 token 互不影响；
+	// ForceRefresh 时带 force_refresh=true，受微信每日次数限制，客户端按 StableForceRefreshInterval 节流
+	ProviderStable Provider = "stable"
+)
+
+const (
+	// WorkWeChatBaseURL 企业微信 API 根地址
+	WorkWeChatBaseURL = "https://qyapi.weixin.qq.com"
+	// workWeChatTokenPath 企业微信获取 token 的路径
+	workWeChatTokenPath = "/cgi-bin/gettoken"
+	// componentTokenPath 第三方平台获取 component_access_token 的路径
+	componentTokenPath = "/cgi-bin/component/api_component_token"
+)
+
+// provider 返回有效的提供方，默认公众号/小程序
+func (c *Config) provider() Provider {
+	if c.Provider == "" {
+		return ProviderMP
+	}
+	return c.Provider
+}
+
+// tokenURL 返回获取 token 的接口地址，BaseURL 为空时使用提供方的官方地址
+func (c *Config) tokenURL() string {
+	if c.provider() == ProviderWorkWeChat {
+		base := WorkWeChatBaseURL
+		if c.BaseURL != "" {
+			base = strings.TrimRight(c.BaseURL, "/")
+		}
+		return base + workWeChatTokenPath
+	}
+	if c.provider() == ProviderComponent {
+		base := "https://api.weixin.qq.com"
+		if c.BaseURL != "" {
+			base = strings.TrimRight(c.BaseURL, "/")
+		}
+		return base + componentTokenPath
+	}
+	if c.provider() == ProviderStable {
+		base := "https://api.weixin.qq.com"
+		if c.BaseURL != "" {
+			base = strings.TrimRight(c.BaseURL, "/")
+		}
+		return base + stableTokenPath
+	}
+	if c.BaseURL == "" {
+		return WeChatTokenAPI
+	}
+	return strings.TrimRight(c.BaseURL, "/") + "/cgi-bin/token"
+}
+
+// newTokenRequest 构造获取 token 的请求：第三方平台与稳定版为 POST JSON，其余为带查询参数的 GET
+func (c *Config) newTokenRequest(ctx context.Context, secret, ticket string) (*http.Request, error) {
+	var payload any
+	switch c.provider() {
+	case ProviderComponent:
+		payload = map[string]string{
+			"component_appid":         c.AppID,
+			"component_appsecret":     secret,
+			"component_verify_ticket": ticket,
+		}
+	case ProviderStable:
+		payload = map[string]any{
+			"grant_type":    "client_credential",
+			"appid":         c.AppID,
+			"secret":        secret,
+			"force_refresh": forceRefreshFromContext(ctx),
+		}
+	default:
+		return http.NewRequestWithContext(ctx, http.MethodGet, c.tokenURL()+"?"+c.tokenParams(secret).Encode(), nil)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// verifyTicket 返回当前的 component_verify_ticket；微信约每 10 分钟推送一次，须由调用方保存并通过回调提供
+func (c *Config) verifyTicket(ctx context.Context) (string, error) {
+	if c.ComponentVerifyTicket == nil {
+		return "", ErrMissingVerifyTicket
+	}
+	ticket, err := c.ComponentVerifyTicket(ctx)
+	if err != nil {
+		return "", fmt.Errorf("component verify ticket: %w", err)
+	}
+	ticket = strings.TrimSpace(ticket)
+	if ticket == "" {
+		return "", ErrMissingVerifyTicket
+	}
+	return ticket, nil
+}
+
+// tokenParams 返回获取 token 的查询参数
+func (c *Config) tokenParams(secret string) url.Values {
+	params := url.Values{}
+	if c.provider() == ProviderWorkWeChat {
+		params.Set("corpid", c.AppID)
+		params.Set("corpsecret", secret)
+		return params
+	}
+	params.Set("grant_type", "client_credential")
+	params.Set("appid", c.AppID)
+	params.Set("secret", secret)
+	return params
+}
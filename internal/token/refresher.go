@@ -0,0 +1,72 @@
+package token
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// refresherRetryInterval 后台刷新失败（或只拿到过期兜底 token）后的重试间隔
+	refresherRetryInterval = 30 * time.Second
+	// refresherMinInterval 两次检查之间的最短间隔，避免 token 有效期异常（如 ExpiresAt 已过）时空转
+	refresherMinInterval = time.Second
+)
+
+// startRefresher 启动后台刷新协程；其 ctx 在构造时派生，Close 时取消，进行中的获取随之中断
+func (m *Manager) startRefresher() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.bgCancel = cancel
+	m.bgDone = make(chan struct{})
+	go m.refreshLoop(ctx)
+}
+
+// refreshLoop 在 token 进入提前刷新窗口时主动刷新，使请求路径始终命中缓存
+// 等待由 Config.Clock 计时（实现 TimerClock 时），测试中推进时钟即可触发
+func (m *Manager) refreshLoop(ctx context.Context) {
+	defer close(m.bgDone)
+
+	for {
+		timer := newTimer(m.clock, m.refreshOnce(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+	}
+}
+
+// refreshOnce 确保缓存中有未进入提前刷新窗口的 token，返回距下次检查的等待时间
+// 缓存中 token 仍有效时 GetToken 直接返回，多实例同时开启时由分布式锁保证只获取一次
+func (m *Manager) refreshOnce(ctx context.Context) time.Duration {
+	result, err := m.GetToken(ctx)
+	if ctx.Err() != nil {
+		return 0
+	}
+	if err != nil {
+		m.logf("background refresh failed: code=%s, err=%v", result.Code, err)
+		return refresherRetryInterval
+	}
+	if result.Code == CodeStale {
+		return refresherRetryInterval
+	}
+
+	// 与 expired() 使用同一窗口规则：有效期较短或 EarlyRefresh 接近有效期时窗口缩为有效期的一半，不会每秒空转
+	wait := refreshAt(result.ExpiresAt, result.expiresIn, m.earlyRefresh).Sub(m.clock.Now())
+	if wait < refresherMinInterval {
+		wait = refresherMinInterval
+	}
+	return wait
+}
+
+// Close 停止后台刷新并等待协程退出；未开启后台刷新时不做任何事，可重复调用
+func (m *Manager) Close() error {
+	m.closeOnce.Do(func() {
+		if m.bgCancel == nil {
+			return
+		}
+		m.bgCancel()
+		<-m.bgDone
+	})
+	return nil
+}
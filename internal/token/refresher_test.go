@@ -0,0 +1,88 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+// waitCalls 等待 fetcher 调用次数达到 n
+func waitCalls(t *testing.T, f *stubFetcher, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for f.calls.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("fetcher calls = %d, want %d", f.calls.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBackgroundRefresherStartStopExit(t *testing.T) {
+	clock := newFakeClock()
+	fetcher := &stubFetcher{}
+	m, err := newTestManager(&Config{Clock: clock, BackgroundRefresh: true}, fetcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	// 启动即获取一次，并等到进入提前刷新窗口（7200s - 5m）
+	waitCalls(t, fetcher, 1)
+	d, ok := clock.waitTimer(2 * time.Second)
+	if !ok {
+		t.Fatal("refresher did not schedule a timer")
+	}
+	if want := 7200*time.Second - defaultEarlyRefresh; d != want {
+		t.Fatalf("refresh wait = %s, want %s", d, want)
+	}
+
+	// 窗口前推进不触发获取
+	clock.Advance(d - time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	if n := fetcher.calls.Load(); n != 1 {
+		t.Fatalf("fetcher calls before refresh window = %d, want 1", n)
+	}
+
+	// 进入窗口后刷新
+	clock.Advance(time.Minute + time.Second)
+	waitCalls(t, fetcher, 2)
+	if _, ok := clock.waitTimer(2 * time.Second); !ok {
+		t.Fatal("refresher did not reschedule after refreshing")
+	}
+
+	// Close 等待协程退出，之后推进时钟不再获取
+	done := make(chan struct{})
+	go func() {
+		m.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not wait for the refresher to exit")
+	}
+	clock.Advance(24 * time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	if n := fetcher.calls.Load(); n != 2 {
+		t.Fatalf("fetcher calls after Close = %d, want 2", n)
+	}
+}
+
+func TestBackgroundRefresherUsesScaledMargin(t *testing.T) {
+	clock := newFakeClock()
+	// 有效期 60s 小于 2 倍默认窗口（5m）：窗口缩为 30s，不应每秒唤醒
+	fetcher := &stubFetcher{expiresIn: 60}
+	m, err := newTestManager(&Config{Clock: clock, BackgroundRefresh: true}, fetcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	waitCalls(t, fetcher, 1)
+	d, ok := clock.waitTimer(2 * time.Second)
+	if !ok {
+		t.Fatal("refresher did not schedule a timer")
+	}
+	if d != 30*time.Second {
+		t.Fatalf("refresh wait = %s, want 30s", d)
+	}
+}
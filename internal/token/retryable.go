@@ -0,0 +1,87 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+// errCodeClass 微信错误码的分类
+type errCodeClass int
+
+const (
+	// errClassOther 未列出的错误码：不可重试，也不视为配置错误
+	errClassOther errCodeClass = iota
+	// errClassTransient 临时故障，稍后重试可能成功
+	errClassTransient
+	// errClassInvalidToken access_token 失效：由 AutoRefreshOnInvalidToken 刷新后重放，不在通用重试中处理；
+	// 由 token 接口返回时表示 AppSecret 错误
+	errClassInvalidToken
+	// errClassConfig 凭证/配置错误，修正配置前重试不会成功
+	errClassConfig
+	// errClassQuota 接口调用超过日限额，需等待配额重置，未启用冷却机制时视为不可重试
+	errClassQuota
+)
+
+// errCodeClasses 微信错误码的分类表，重试、负缓存与健康检查的凭证错误判断均由此派生
+var errCodeClasses = map[int]errCodeClass{
+	-1:    errClassTransient,    // 系统繁忙
+	45011: errClassTransient,    // API 调用太频繁，请稍候再试
+	40001: errClassInvalidToken, // access_token 无效或 AppSecret 错误
+	40013: errClassConfig,       // 不合法的 AppID
+	40125: errClassConfig,       // 不合法的 AppSecret
+	40164: errClassConfig,       // 调用接口的 IP 不在白名单中
+	41002: errClassConfig,       // 缺少 appid 参数
+	41004: errClassConfig,       // 缺少 secret 参数
+	48001: errClassConfig,       // api 功能未授权
+	45009: errClassQuota,        // 接口调用超过日限额
+}
+
+// isRetryable 按微信错误码与 HTTP 状态码判断是否可重试；errcode 为 0 时只看状态码
+// 可重试：errClassTransient（-1、45011）、429、5xx；其余错误码不可重试
+func isRetryable(errCode int, httpStatus int) bool {
+	if errCode != 0 {
+		return errCodeClasses[errCode] == errClassTransient
+	}
+	return httpStatus == http.StatusTooManyRequests || httpStatus >= 500
+}
+
+// isConfigErrCode 是否为配置类错误码（errClassConfig），可负缓存
+func isConfigErrCode(errCode int) bool {
+	return errCodeClasses[errCode] == errClassConfig
+}
+
+// IsCredentialErrCode 是否为凭证/配置类错误码：配置错误（如 AppID、AppSecret 不合法、IP 未加白）及 40001
+func IsCredentialErrCode(errCode int) bool {
+	class := errCodeClasses[errCode]
+	return class == errClassConfig || class == errClassInvalidToken
+}
+
+// IsRetryable 判断 SDK 返回的错误是否值得调用方重试
+// 微信业务错误与 HTTP 状态码按 isRetryable 分类；网络错误、非 JSON 响应（微信故障页）可重试；
+// ctx 取消/超时、参数校验等其余错误不可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryable(apiErr.ErrCode, 0)
+	}
+	var statusErr *transport.StatusError
+	if errors.As(err, &statusErr) {
+		return isRetryable(0, statusErr.StatusCode)
+	}
+	if errors.Is(err, ErrNonJSONResponse) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
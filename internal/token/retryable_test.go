@@ -0,0 +1,35 @@
+package token
+
+import "testing"
+
+func TestErrCodeClassesDriveEveryClassifier(t *testing.T) {
+	tests := []struct {
+		errCode    int
+		retryable  bool
+		negative   bool
+		credential bool
+	}{
+		{-1, true, false, false},
+		{45011, true, false, false},
+		{40001, false, false, true},
+		{40013, false, true, true},
+		{40125, false, true, true},
+		{40164, false, true, true},
+		{41002, false, true, true},
+		{41004, false, true, true},
+		{48001, false, true, true},
+		{45009, false, false, false},
+		{40003, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryable(tt.errCode, 0); got != tt.retryable {
+			t.Errorf("isRetryable(%d) = %v, want %v", tt.errCode, got, tt.retryable)
+		}
+		if got := isConfigErrCode(tt.errCode); got != tt.negative {
+			t.Errorf("isConfigErrCode(%d) = %v, want %v", tt.errCode, got, tt.negative)
+		}
+		if got := IsCredentialErrCode(tt.errCode); got != tt.credential {
+			t.Errorf("IsCredentialErrCode(%d) = %v, want %v", tt.errCode, got, tt.credential)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// gatedFetcher 在 release 关闭前阻塞，entered 在首次进入时关闭
+type gatedFetcher struct {
+	stubFetcher
+	once    sync.Once
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (f *gatedFetcher) Fetch(ctx context.Context) (*TokenInfo, Code, error) {
+	f.once.Do(func() { close(f.entered) })
+	<-f.release
+	return f.stubFetcher.Fetch(ctx)
+}
+
+func TestRotateSecretSkipsRefetchAfterConcurrentRotation(t *testing.T) {
+	mr := miniredis.RunT(t)
+	fetcher := &gatedFetcher{entered: make(chan struct{}), release: make(chan struct{})}
+	newInstance := func() *Manager {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		m, err := newTestManager(&Config{RedisClient: client}, fetcher)
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		t.Cleanup(func() { m.Close() })
+		return m
+	}
+	a, b := newInstance(), newInstance()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	codes := make([]Code, 2)
+	errs := make([]error, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		codes[0], errs[0] = a.RotateSecret(ctx, "secret-0002")
+	}()
+	<-fetcher.entered
+
+	// b 在 a 持锁获取期间开始轮换，拿到锁时 a 已写入新 token
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		codes[1], errs[1] = b.RotateSecret(ctx, "secret-0002")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(fetcher.release)
+	wg.Wait()
+
+	for i := range codes {
+		if errs[i] != nil || codes[i] != CodeOK {
+			t.Fatalf("RotateSecret #%d: code=%v err=%v", i, codes[i], errs[i])
+		}
+	}
+	if got := fetcher.calls.Load(); got != 1 {
+		t.Fatalf("fetch calls = %d, want 1", got)
+	}
+	if got := b.EffectiveConfig().AppSecret; got != "****0002" {
+		t.Fatalf("b AppSecret = %q, want ****0002", got)
+	}
+}
+
+func TestRotateSecretRefetchesOverPlainRefresh(t *testing.T) {
+	fetcher := &stubFetcher{}
+	m, err := newTestManager(nil, fetcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	// 轮换开始后写入、但非轮换获取的 token 可能来自旧 secret，不能沿用
+	key := m.getCacheKey()
+	now := time.Now()
+	stale := &TokenInfo{AccessToken: "old-secret-token", ExpiresIn: 7200, ExpiresAt: now.Add(2 * time.Hour), FetchedAt: now.Add(time.Minute)}
+	if err := m.cache.Set(context.Background(), key, stale, time.Hour); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := m.RotateSecret(context.Background(), "secret-0002"); err != nil {
+		t.Fatalf("RotateSecret: %v", err)
+	}
+	if got := fetcher.calls.Load(); got != 1 {
+		t.Fatalf("fetch calls = %d, want 1", got)
+	}
+	tk, _, _ := m.GetAccessToken(context.Background())
+	if tk != "token-1" {
+		t.Fatalf("token = %q, want token-1", tk)
+	}
+}
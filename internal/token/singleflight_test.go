@@ -0,0 +1,52 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentMissesShareOneFailingFetch(t *testing.T) {
+	errUpstream := errors.New("upstream unavailable")
+	fetcher := &gatedFetcher{
+		stubFetcher: stubFetcher{err: errUpstream, code: CodeHTTP},
+		entered:     make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	m, err := newTestManager(nil, fetcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	const waiters = 50
+	var started, done sync.WaitGroup
+	codes := make([]Code, waiters)
+	errs := make([]error, waiters)
+	started.Add(waiters)
+	done.Add(waiters)
+	for i := 0; i < waiters; i++ {
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			_, codes[i], errs[i] = m.GetAccessToken(context.Background())
+		}(i)
+	}
+	started.Wait()
+	<-fetcher.entered
+	// 留出时间让其余协程进入 singleflight 等待，而不是在失败返回后各自重新获取
+	time.Sleep(50 * time.Millisecond)
+	close(fetcher.release)
+	done.Wait()
+
+	if n := fetcher.calls.Load(); n != 1 {
+		t.Fatalf("fetcher called %d times, want 1 shared fetch", n)
+	}
+	for i := 0; i < waiters; i++ {
+		if codes[i] != CodeHTTP || !errors.Is(errs[i], errUpstream) {
+			t.Fatalf("waiter %d: code=%v err=%v, want the shared CodeHTTP failure", i, codes[i], errs[i])
+		}
+	}
+}
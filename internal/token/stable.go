@@ -0,0 +1,85 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// stableTokenPath 获取稳定版 access_token 的路径
+	stableTokenPath = "/cgi-bin/stable_token"
+	// defaultStableForceInterval 两次 force_refresh 的默认最小间隔：微信限制每天 20 次，24h/20 保证不超出
+	defaultStableForceInterval = 24 * time.Hour / 20
+)
+
+// forceRefreshKey ctx 中「本次以 force_refresh=true 请求 stable_token」标记的 key
+type forceRefreshKey struct{}
+
+// withForceRefresh 标记本次获取使用 force_refresh=true
+func withForceRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceRefreshKey{}, true)
+}
+
+// forceRefreshFromContext 本次获取是否使用 force_refresh=true
+func forceRefreshFromContext(ctx context.Context) bool {
+	force, _ := ctx.Value(forceRefreshKey{}).(bool)
+	return force
+}
+
+// stableForceInterval 返回两次 force_refresh 的最小间隔，默认 72 分钟
+func (c *Config) stableForceInterval() time.Duration {
+	if c.StableForceRefreshInterval <= 0 {
+		return defaultStableForceInterval
+	}
+	return c.StableForceRefreshInterval
+}
+
+// getStableForceKey 记录上次 force_refresh 的哨兵 key：{prefix}:stable_force:{appID}
+func (m *Manager) getStableForceKey() string {
+	return m.config.keyParts().format("stable_force")
+}
+
+// stableForceAllowed 是否允许本次 force_refresh：间隔内已强制刷新过（哨兵 key 未过期）时返回 false
+// 哨兵记录在共享缓存中，多实例共同受限；调用方需持有本地锁（及分布式锁）
+func (m *Manager) stableForceAllowed(ctx context.Context) (bool, error) {
+	last, err := m.getPrimary(ctx, m.getStableForceKey())
+	if err != nil {
+		return false, err
+	}
+	return last == nil || !m.clock.Now().Before(last.ExpiresAt), nil
+}
+
+// recordStableForce 写入哨兵 key，间隔结束后自动过期
+func (m *Manager) recordStableForce(ctx context.Context) {
+	interval := m.config.stableForceInterval()
+	now := m.clock.Now()
+	sentinel := &TokenInfo{
+		ExpiresIn: int(interval / time.Second),
+		ExpiresAt: now.Add(interval),
+	}
+	if err := m.cache.Set(ctx, m.getStableForceKey(), sentinel, interval); err != nil {
+		m.logf("record stable_token force refresh: %v", err)
+	}
+}
+
+// refreshStable 稳定版 token 的强制刷新：未超出频率限制时先记录哨兵再以 force_refresh=true 获取，
+// 否则退回普通获取（微信返回当前仍有效的 token），结果码为 CodeForceRefreshThrottled
+// 哨兵在请求前写入：请求超时或失败时微信可能已计入次数，失败后立即重试也不会绕过限制
+func (m *Manager) refreshStable(ctx context.Context, cacheKey string) (*TokenResult, error) {
+	allowed, err := m.stableForceAllowed(ctx)
+	if err != nil {
+		return failedResult(CodeCacheGet), fmt.Errorf("get stable_token force sentinel: %w", err)
+	}
+	if !allowed {
+		m.logf("stable_token force_refresh throttled (at most once per %s), falling back to a normal fetch", m.config.stableForceInterval())
+		result, err := m.refresh(ctx, cacheKey)
+		if err == nil && result.Code == CodeOK {
+			result.Code = CodeForceRefreshThrottled
+		}
+		return result, err
+	}
+
+	m.recordStableForce(ctx)
+	return m.refresh(withForceRefresh(ctx), cacheKey)
+}
@@ -0,0 +1,56 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStableForceSentinelRecordedOnFailure(t *testing.T) {
+	var forced, fail atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ForceRefresh bool `json:"force_refresh"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.ForceRefresh {
+			forced.Add(1)
+		}
+		if fail.Load() == 1 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"stable-token","expires_in":7200}`)
+	}))
+	defer srv.Close()
+
+	m, err := NewManager(&Config{
+		AppID:     "wx_stable",
+		AppSecret: "secret",
+		Provider:  ProviderStable,
+		BaseURL:   srv.URL,
+		Cache:     NewMemoryCache(),
+	})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	// force_refresh 请求失败：微信可能已计入次数，间隔内的下一次 ForceRefresh 不得再带 force_refresh
+	fail.Store(1)
+	if _, _, err := m.ForceRefresh(context.Background(), ""); err == nil {
+		t.Fatal("ForceRefresh succeeded against a failing server")
+	}
+	fail.Store(0)
+	_, code, err := m.ForceRefresh(context.Background(), "")
+	if err != nil || code != CodeForceRefreshThrottled {
+		t.Fatalf("second ForceRefresh: code=%v err=%v, want CodeForceRefreshThrottled", code, err)
+	}
+	if got := forced.Load(); got != 1 {
+		t.Fatalf("force_refresh requests = %d, want 1", got)
+	}
+}
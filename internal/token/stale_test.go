@@ -0,0 +1,78 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// staleBackend 构造待测缓存后端；advance 同时推进 Manager 时钟与后端自身的过期计时
+type staleBackend struct {
+	name  string
+	setup func(t *testing.T, cfg *Config, clock *fakeClock) (advance func(time.Duration))
+}
+
+var staleBackends = []staleBackend{
+	{"memory", func(t *testing.T, cfg *Config, clock *fakeClock) func(time.Duration) {
+		cfg.Cache = NewMemoryCache()
+		return clock.Advance
+	}},
+	{"file", func(t *testing.T, cfg *Config, clock *fakeClock) func(time.Duration) {
+		cfg.Cache = NewFileCache(filepath.Join(t.TempDir(), "tokens.json"))
+		return clock.Advance
+	}},
+	{"redis", func(t *testing.T, cfg *Config, clock *fakeClock) func(time.Duration) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		cfg.RedisClient = client
+		return func(d time.Duration) {
+			clock.Advance(d)
+			mr.FastForward(d)
+		}
+	}},
+}
+
+func TestStaleIfErrorPerBackend(t *testing.T) {
+	for _, b := range staleBackends {
+		t.Run(b.name, func(t *testing.T) {
+			clock := newFakeClock()
+			cfg := &Config{Clock: clock, StaleIfError: true}
+			advance := b.setup(t, cfg, clock)
+			fetcher := &stubFetcher{}
+			m, err := newTestManager(cfg, fetcher)
+			if err != nil {
+				t.Fatalf("NewManager: %v", err)
+			}
+			defer m.Close()
+
+			ctx := context.Background()
+			first, err := m.GetToken(ctx)
+			if err != nil {
+				t.Fatalf("GetToken: %v", err)
+			}
+
+			// 过期 1 分钟后微信不可达：在默认 5 分钟窗口内返回过期 token
+			advance(7200*time.Second + time.Minute)
+			fetcher.err, fetcher.code = errors.New("connection refused"), CodeHTTP
+			result, err := m.GetToken(ctx)
+			if err != nil {
+				t.Fatalf("GetToken within stale window: %v", err)
+			}
+			if result.Code != CodeStale || result.AccessToken != first.AccessToken {
+				t.Fatalf("GetToken = %+v, want stale %q", result, first.AccessToken)
+			}
+
+			// 超出窗口：不再兜底
+			advance(10 * time.Minute)
+			if result, err := m.GetToken(ctx); err == nil {
+				t.Fatalf("GetToken beyond stale window = %+v, want error", result)
+			}
+		})
+	}
+}
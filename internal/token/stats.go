@@ -0,0 +1,130 @@
+package token
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats 运行时累计计数快照
+type Stats struct {
+	// TokenFetches 向微信获取 token 的次数（无论成败）
+	TokenFetches uint64
+	// TokenFetchFailures 获取 token 失败的次数
+	TokenFetchFailures uint64
+	// CacheHits 缓存命中有效 token 的次数
+	CacheHits uint64
+	// CacheMisses 缓存未命中或 token 已过期的次数
+	CacheMisses uint64
+	// LockAcquisitions 成功获取分布式锁的次数
+	LockAcquisitions uint64
+	// LockFailures 获取分布式锁失败的次数
+	LockFailures uint64
+	// LockOverruns 持锁时间超过锁 TTL 的次数（锁在持有期间已过期）
+	LockOverruns uint64
+	// WeChatErrors 获取 token 时微信返回的错误，按 errcode 分组计数
+	WeChatErrors map[int]uint64
+	// LastRefresh 最近一次成功从微信获取 token 的时间；从未获取时为零值
+	LastRefresh time.Time
+}
+
+// stats 以原子计数实现 Metrics，与用户配置的 Metrics 在同一位置更新
+type stats struct {
+	fetches      atomic.Uint64
+	fetchFails   atomic.Uint64
+	cacheHits    atomic.Uint64
+	cacheMisses  atomic.Uint64
+	locks        atomic.Uint64
+	lockFails    atomic.Uint64
+	lockOverruns atomic.Uint64
+	lastRefresh  atomic.Int64 // UnixNano，0 表示从未刷新
+	errCodesMu   sync.Mutex
+	errCodeCount map[int]uint64
+}
+
+func (s *stats) CacheHit()  { s.cacheHits.Add(1) }
+func (s *stats) CacheMiss() { s.cacheMisses.Add(1) }
+
+func (s *stats) TokenFetch(code Code, _ time.Duration) {
+	s.fetches.Add(1)
+	if code != CodeOK {
+		s.fetchFails.Add(1)
+	}
+}
+
+func (s *stats) LockAcquire(_ time.Duration, err error) {
+	if err != nil {
+		s.lockFails.Add(1)
+		return
+	}
+	s.locks.Add(1)
+}
+
+// recordFetch 记录一次获取结果：成功时更新刷新时间，微信业务错误按 errcode 计数
+func (s *stats) recordFetch(now time.Time, err error) {
+	if err == nil {
+		s.lastRefresh.Store(now.UnixNano())
+		return
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	s.errCodesMu.Lock()
+	defer s.errCodesMu.Unlock()
+	if s.errCodeCount == nil {
+		s.errCodeCount = make(map[int]uint64)
+	}
+	s.errCodeCount[apiErr.ErrCode]++
+}
+
+// snapshot 返回当前计数的副本
+func (s *stats) snapshot() Stats {
+	out := Stats{
+		TokenFetches:       s.fetches.Load(),
+		TokenFetchFailures: s.fetchFails.Load(),
+		CacheHits:          s.cacheHits.Load(),
+		CacheMisses:        s.cacheMisses.Load(),
+		LockAcquisitions:   s.locks.Load(),
+		LockFailures:       s.lockFails.Load(),
+		LockOverruns:       s.lockOverruns.Load(),
+	}
+	if ns := s.lastRefresh.Load(); ns != 0 {
+		out.LastRefresh = time.Unix(0, ns)
+	}
+
+	s.errCodesMu.Lock()
+	defer s.errCodesMu.Unlock()
+	out.WeChatErrors = make(map[int]uint64, len(s.errCodeCount))
+	for k, v := range s.errCodeCount {
+		out.WeChatErrors[k] = v
+	}
+	return out
+}
+
+// teeMetrics 将回调同时分发给内置计数与用户配置的 Metrics
+type teeMetrics struct {
+	stats *stats
+	user  Metrics
+}
+
+func (t teeMetrics) CacheHit() {
+	t.stats.CacheHit()
+	t.user.CacheHit()
+}
+
+func (t teeMetrics) CacheMiss() {
+	t.stats.CacheMiss()
+	t.user.CacheMiss()
+}
+
+func (t teeMetrics) TokenFetch(code Code, d time.Duration) {
+	t.stats.TokenFetch(code, d)
+	t.user.TokenFetch(code, d)
+}
+
+func (t teeMetrics) LockAcquire(wait time.Duration, err error) {
+	t.stats.LockAcquire(wait, err)
+	t.user.LockAcquire(wait, err)
+}
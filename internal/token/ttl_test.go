@@ -0,0 +1,62 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestZeroExpiresInIsRejected(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"tk","expires_in":0}`))
+	}))
+	defer srv.Close()
+
+	m, err := NewManager(&Config{AppID: "wx_ttl", AppSecret: "secret", BaseURL: srv.URL, RedisClient: client})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	_, code, err := m.GetAccessToken(context.Background())
+	if code != CodeInvalidResponse || !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("GetAccessToken: code=%v err=%v, want CodeInvalidResponse", code, err)
+	}
+	if mr.Exists(m.getCacheKey()) {
+		t.Fatal("token with expires_in=0 was written to redis")
+	}
+}
+
+func TestRedisCacheRejectsNonPositiveTTL(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	cache := NewRedisCache(client)
+
+	tk := &TokenInfo{AccessToken: "tk", ExpiresIn: 7200, ExpiresAt: time.Now().Add(time.Hour)}
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if err := cache.Set(context.Background(), "wxgo:token:wx_ttl", tk, ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Fatalf("Set(ttl=%s) = %v, want ErrInvalidTTL", ttl, err)
+		}
+	}
+	if mr.Exists("wxgo:token:wx_ttl") {
+		t.Fatal("key written without expiry")
+	}
+
+	if err := cache.Set(context.Background(), "wxgo:token:wx_ttl", tk, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if ttl := mr.TTL("wxgo:token:wx_ttl"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("redis TTL = %s, want (0, 1m]", ttl)
+	}
+}
@@ -2,16 +2,88 @@ package token
 
 import "time"
 
+// defaultEarlyRefresh 提前刷新窗口：token 在实际过期前这段时间内即视为过期
+const defaultEarlyRefresh = 5 * time.Minute
+
 // TokenInfo Access Token 信息
 type TokenInfo struct {
 	AccessToken string    `json:"access_token"`
 	ExpiresIn   int       `json:"expires_in"` // 过期时间（秒）
 	ExpiresAt   time.Time // 实际过期时间
+	// FetchedAt 从微信获取的时间，由 Manager 写入缓存前记录；旧版本写入的条目为零值
+	FetchedAt time.Time `json:"fetched_at"`
+	// Rotated 为 true 表示由 RotateSecret 获取写入
+	Rotated bool `json:"rotated,omitempty"`
 }
 
-// IsExpired 检查 Token 是否已过期
-// 提前 5 分钟刷新，避免边界情况
+// IsExpired 以系统时间和默认的 5 分钟提前刷新窗口检查 Token 是否已过期
+// 不读取 Config.EarlyRefresh、Config.Clock 与 TTLJitter；需与 Manager 判断一致时使用 Manager.Expired
 func (t *TokenInfo) IsExpired() bool {
-	return time.Now().Add(5 * time.Minute).After(t.ExpiresAt)
+	return t.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt 以 now 为当前时间、默认的 5 分钟提前刷新窗口检查 Token 是否已过期
+func (t *TokenInfo) IsExpiredAt(now time.Time) bool {
+	return t.ExpiredWithin(now, defaultEarlyRefresh)
+}
+
+// ExpiredWithin 以 now 为当前时间、margin 为提前刷新窗口检查 Token 是否已过期
+// 微信返回的 expires_in 可能远小于 7200（如其他系统刚获取过 token），有效期不足 2*margin 时
+// 窗口缩为有效期的一半，避免 token 刚获取即被判定过期而反复刷新；margin 为 0 时即 now.After(ExpiresAt)
+func (t *TokenInfo) ExpiredWithin(now time.Time, margin time.Duration) bool {
+	return now.After(refreshAt(t.ExpiresAt, t.ExpiresIn, margin))
 }
 
+// refreshAt 返回 token 进入提前刷新窗口的时刻：ExpiresAt 减去窗口，有效期不足 2*margin 时窗口为有效期的一半
+func refreshAt(expiresAt time.Time, expiresIn int, margin time.Duration) time.Time {
+	if lifetime := time.Duration(expiresIn) * time.Second; lifetime > 0 && lifetime < 2*margin {
+		margin = lifetime / 2
+	}
+	return expiresAt.Add(-margin)
+}
+
+// TokenResult 一次获取 token 的详细结果
+type TokenResult struct {
+	// AccessToken 获取到的 token；失败时为空
+	AccessToken string
+	// ExpiresAt token 的实际过期时间
+	ExpiresAt time.Time
+	// FromCache 是否直接命中缓存（false 表示本次向微信获取）
+	FromCache bool
+	// Code 结果码
+	Code Code
+
+	// expiresIn token 的有效期（秒），用于按与过期判断相同的规则计算后台刷新时间
+	expiresIn int
+}
+
+// cachedResult 由缓存命中的 token 构建结果
+func cachedResult(t *TokenInfo) *TokenResult {
+	return &TokenResult{
+		AccessToken: t.AccessToken,
+		ExpiresAt:   t.ExpiresAt,
+		FromCache:   true,
+		Code:        CodeOK,
+		expiresIn:   t.ExpiresIn,
+	}
+}
+
+// staleResult 由缓存中已过期的 token 构建兜底结果
+func staleResult(t *TokenInfo) *TokenResult {
+	return &TokenResult{
+		AccessToken: t.AccessToken,
+		ExpiresAt:   t.ExpiresAt,
+		FromCache:   true,
+		Code:        CodeStale,
+	}
+}
+
+// failedResult 构建失败结果
+func failedResult(code Code) *TokenResult {
+	return &TokenResult{Code: code}
+}
+
+// secondsDuration 将秒数转换为 time.Duration
+func secondsDuration(secs int) time.Duration {
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,85 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShortExpiresInScalesEarlyRefresh(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tk := &TokenInfo{AccessToken: "tk", ExpiresIn: 120, ExpiresAt: now.Add(120 * time.Second)}
+
+	// 默认 5 分钟窗口大于有效期：窗口缩为 60 秒，而不是刚获取即过期
+	tests := []struct {
+		elapsed time.Duration
+		expired bool
+	}{
+		{0, false},
+		{59 * time.Second, false},
+		{60 * time.Second, false},
+		{61 * time.Second, true},
+	}
+	for _, tt := range tests {
+		if got := tk.IsExpiredAt(now.Add(tt.elapsed)); got != tt.expired {
+			t.Fatalf("IsExpiredAt(+%s) = %v, want %v", tt.elapsed, got, tt.expired)
+		}
+	}
+
+	// 有效期充足时窗口不变
+	long := &TokenInfo{AccessToken: "tk", ExpiresIn: 7200, ExpiresAt: now.Add(7200 * time.Second)}
+	if long.IsExpiredAt(now.Add(7200*time.Second-defaultEarlyRefresh-time.Second)) || !long.IsExpiredAt(now.Add(7200*time.Second-defaultEarlyRefresh+time.Second)) {
+		t.Fatal("7200s token should enter the 5 minute early-refresh window exactly")
+	}
+}
+
+func TestShortExpiresInDoesNotRefetchLoop(t *testing.T) {
+	clock := newFakeClock()
+	fetcher := &stubFetcher{expiresIn: 120}
+	m, err := newTestManager(&Config{Clock: clock}, fetcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if _, err := m.GetToken(ctx); err != nil {
+			t.Fatalf("GetToken: %v", err)
+		}
+	}
+	if got := fetcher.calls.Load(); got != 1 {
+		t.Fatalf("fetch calls = %d, want 1 for a fresh 120s token", got)
+	}
+
+	clock.Advance(61 * time.Second)
+	if _, err := m.GetToken(ctx); err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if got := fetcher.calls.Load(); got != 2 {
+		t.Fatalf("fetch calls = %d, want 2 after entering the scaled window", got)
+	}
+}
+
+func TestManagerExpiredUsesConfiguredWindowAndClock(t *testing.T) {
+	clock := newFakeClock()
+	zero := time.Duration(0)
+	m, err := newTestManager(&Config{Clock: clock, EarlyRefresh: &zero}, &stubFetcher{})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	// 1 分钟后过期：默认 5 分钟窗口视为过期，EarlyRefresh=0 的 Manager 仍视为有效
+	tk := &TokenInfo{AccessToken: "tk", ExpiresIn: 7200, ExpiresAt: clock.Now().Add(time.Minute)}
+	if !tk.IsExpiredAt(clock.Now()) {
+		t.Fatal("IsExpiredAt with the default window = false, want true")
+	}
+	if m.Expired(tk) {
+		t.Fatal("Expired with EarlyRefresh=0 = true, want false")
+	}
+	clock.Advance(time.Minute + time.Second)
+	if !m.Expired(tk) {
+		t.Fatal("Expired after ExpiresAt on the manager clock = false, want true")
+	}
+}
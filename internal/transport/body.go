@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultMaxResponseBytes 普通接口响应体的默认大小上限
+	DefaultMaxResponseBytes int64 = 10 << 20
+	// DefaultMaxMediaBytes 素材、二维码图片等下载的默认大小上限
+	DefaultMaxMediaBytes int64 = 32 << 20
+)
+
+// ErrResponseTooLarge 响应体超过大小上限
+var ErrResponseTooLarge = errors.New("wxgo: response body too large")
+
+// ReadBody 读取响应体，最多 limit 字节；超出时返回 ErrResponseTooLarge，避免异常响应耗尽内存
+// limit <= 0 时使用 DefaultMaxResponseBytes
+func ReadBody(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, nil
+}
+
+// utf8BOM UTF-8 字节序标记
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// TrimJSONBody 去掉响应体开头的 UTF-8 BOM 及首尾空白
+// 微信偶尔以 text/plain 返回 JSON 并带 BOM 或尾随换行，json.Unmarshal 会因 BOM 报错
+func TrimJSONBody(body []byte) []byte {
+	body = bytes.TrimPrefix(body, utf8BOM)
+	return bytes.TrimSpace(body)
+}
+
+// maxSnippetRunes 错误信息中保留的响应体最大字符数
+const maxSnippetRunes = 120
+
+// sensitiveParamPattern 响应体中可能回显的敏感参数（如 HTML 错误页中带出的请求地址）
+var sensitiveParamPattern = regexp.MustCompile(`(?i)(secret|access_token|ticket)=[^&\s"'<>]*`)
+
+// sensitiveFieldPattern 响应 JSON 中的凭据字段
+var sensitiveFieldPattern = regexp.MustCompile(`"(access_token|component_access_token|refresh_token|session_key|ticket|secret)"(\s*):(\s*)"[^"]*"`)
+
+// RedactBody 返回抹去凭据后的响应体副本：JSON 中的 access_token、ticket、session_key 等字段值，以及回显的 secret= 等参数
+func RedactBody(body []byte) []byte {
+	out := sensitiveFieldPattern.ReplaceAll(body, []byte(`"$1"$2:$3"REDACTED"`))
+	return sensitiveParamPattern.ReplaceAll(out, []byte("$1=REDACTED"))
+}
+
+// LooksLikeJSON 去掉 BOM 与空白后是否以 { 或 [ 开头
+func LooksLikeJSON(body []byte) bool {
+	body = TrimJSONBody(body)
+	return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
+
+// BodySnippet 返回适合放入错误信息的响应体片段：合并空白、截断过长内容，并抹去 secret/access_token 等参数值
+func BodySnippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(TrimJSONBody(body))), " ")
+	s = sensitiveParamPattern.ReplaceAllString(s, "$1=REDACTED")
+	if r := []rune(s); len(r) > maxSnippetRunes {
+		s = string(r[:maxSnippetRunes]) + "..."
+	}
+	return s
+}
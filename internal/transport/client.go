@@ -2,36 +2,148 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// RequestIDHeader 透传请求 ID 使用的 Header
+const RequestIDHeader = "X-Request-Id"
+
+// StatusError 微信接口返回了非 2xx 的 HTTP 状态码
+type StatusError struct {
+	// Target 请求的接口（路径或描述），不含 query
+	Target     string
+	StatusCode int
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("wechat %s status: %d", e.Target, e.StatusCode)
+}
+
 // Client HTTP 传输层客户端封装
 type Client struct {
 	http      *http.Client
 	userAgent string
+	requestID func(ctx context.Context) string
+	retry     RetryPolicy
+	headers   http.Header
+	tap       func(endpoint string, status int, body []byte)
 }
 
+const (
+	// defaultMaxIdleConns 连接池最大空闲连接数
+	defaultMaxIdleConns = 100
+	// defaultMaxIdleConnsPerHost 单 host 最大空闲连接数；微信接口集中在少数几个 host，Go 默认的 2 过小
+	defaultMaxIdleConnsPerHost = 20
+	// defaultIdleConnTimeout 空闲连接保活时间
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
 // NewClient 创建 HTTP 客户端
 func NewClient() *Client {
 	return &Client{
 		http: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: NewTransport(),
 		},
 		userAgent: "wxgo/1.0.0",
 	}
 }
 
+// NewTransport 创建针对微信接口调优的连接池（基于 http.DefaultTransport 克隆）
+func NewTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = defaultMaxIdleConns
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = defaultIdleConnTimeout
+	return t
+}
+
 // Do 执行 HTTP 请求
-// 统一入口，后续可在此添加 retry、backoff、metrics、trace 等功能
+// 统一入口：设置公共 Header，按 RetryPolicy 重试，并对错误中的 URL 脱敏
 func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	// 默认 Header 只补充请求中未设置的项，不覆盖 Content-Type 等单次请求自带的 Header
+	for k, v := range c.headers {
+		if _, ok := req.Header[k]; !ok {
+			req.Header[k] = append([]string(nil), v...)
+		}
+	}
+
 	// 统一设置 User-Agent
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	// 执行请求
-	return c.http.Do(req.WithContext(ctx))
+	// 从 ctx 提取请求 ID，便于与内部链路关联
+	if c.requestID != nil && req.Header.Get(RequestIDHeader) == "" {
+		if id := c.requestID(ctx); id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+	}
+
+	req = req.WithContext(ctx)
+	for attempt := 0; ; attempt++ {
+		resp, err := c.http.Do(req)
+		if attempt >= c.retry.MaxRetries || ctx.Err() != nil || !retryable(req, resp, err) {
+			return finish(resp, err)
+		}
+
+		// 等待超出 ctx 截止时间则不再重试，直接返回本次结果
+		wait := c.retry.retryWait(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return finish(resp, err)
+		}
+		next, ok := rewindRequest(ctx, req)
+		if !ok {
+			return finish(resp, err)
+		}
+		if resp != nil {
+			drainAndClose(resp)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// finish 返回请求结果；错误中的 URL 去掉 query，避免 access_token/secret 泄漏到日志
+func finish(resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, RedactURLError(err)
+	}
+	return resp, nil
+}
+
+// RedactURLError 将 *url.Error 中的 URL 替换为仅含 scheme/host/path 的形式
+// net/http 的错误会带上完整请求地址，而微信接口把 secret/access_token 放在 query 中
+// 保留原始的底层错误，errors.Is(err, context.DeadlineExceeded) 等判断不受影响
+func RedactURLError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	return &url.Error{
+		Op:  urlErr.Op,
+		URL: RedactURL(urlErr.URL),
+		Err: urlErr.Err,
+	}
+}
+
+// RedactURL 去掉 URL 中的 query、fragment 与用户信息；无法解析时返回占位符
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<redacted>"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
 }
 
 // SetTimeout 设置请求超时时间
@@ -39,3 +151,73 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.http.Timeout = timeout
 }
 
+// SetUserAgent 设置 User-Agent；为空时保持默认
+func (c *Client) SetUserAgent(ua string) {
+	if ua != "" {
+		c.userAgent = ua
+	}
+}
+
+// SetRequestIDFunc 设置从 ctx 提取请求 ID 的函数；返回非空时写入 X-Request-Id
+func (c *Client) SetRequestIDFunc(fn func(ctx context.Context) string) {
+	c.requestID = fn
+}
+
+// SetDefaultHeaders 设置附加到每个请求的默认 Header；请求已设置的同名 Header 优先
+// 默认 Header 中的 User-Agent 优先于 SetUserAgent 的值
+func (c *Client) SetDefaultHeaders(h http.Header) {
+	headers := make(http.Header, len(h))
+	for k, v := range h {
+		headers[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+	}
+	c.headers = headers
+}
+
+// SetResponseTap 设置响应旁路回调（诊断用）；nil 表示关闭
+func (c *Client) SetResponseTap(fn func(endpoint string, status int, body []byte)) {
+	c.tap = fn
+}
+
+// Tap 将一次请求的响应交给旁路回调；未设置回调时不做任何事
+// endpoint 为请求路径（不含 query），body 为已读取的响应体（非 2xx 时为 nil），传入前抹去凭据
+func (c *Client) Tap(req *http.Request, status int, body []byte) {
+	if c.tap == nil {
+		return
+	}
+	if body != nil {
+		body = RedactBody(body)
+	}
+	c.tap(req.URL.Path, status, body)
+}
+
+// Timeout 返回单次请求的超时时间
+func (c *Client) Timeout() time.Duration {
+	return c.http.Timeout
+}
+
+// Retry 返回生效的重试策略，MaxWait 已填充默认值
+func (c *Client) Retry() RetryPolicy {
+	p := c.retry
+	p.MaxWait = p.maxWait()
+	return p
+}
+
+// SetRetry 设置重试策略；默认不重试
+func (c *Client) SetRetry(p RetryPolicy) {
+	c.retry = p
+}
+
+// SetTransport 替换底层连接池
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.http.Transport = rt
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接，进行中的请求不受影响
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+// Transport 返回底层连接池，便于其他组件复用
+func (c *Client) Transport() http.RoundTripper {
+	return c.http.Transport
+}
@@ -0,0 +1,21 @@
+package transport
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTimeout 调用方 ctx 无截止时间时使用的默认超时
+const DefaultTimeout = 10 * time.Second
+
+// WithDefaultTimeout 若 ctx 没有截止时间，派生一个带 timeout 的子 ctx；否则原样返回
+// timeout <= 0 时使用 DefaultTimeout；调用方更短的截止时间始终优先
+func WithDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,125 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRetryMaxWait 单次重试等待的默认上限
+	defaultRetryMaxWait = 10 * time.Second
+	// defaultRetryBaseBackoff 服务端未给出 Retry-After 时的首次退避时长，之后按 2 倍递增
+	defaultRetryBaseBackoff = 200 * time.Millisecond
+)
+
+// RetryPolicy 重试策略；零值表示不重试
+// 仅重试 429/503 响应，以及 GET/HEAD 请求的网络错误；有请求体但无法重放（GetBody 为空）的请求不重试
+type RetryPolicy struct {
+	// MaxRetries 最大重试次数（不含首次请求）
+	MaxRetries int
+	// MaxWait 单次等待上限，Retry-After 超过该值时按该值等待；默认 10s
+	MaxWait time.Duration
+}
+
+func (p RetryPolicy) maxWait() time.Duration {
+	if p.MaxWait <= 0 {
+		return defaultRetryMaxWait
+	}
+	return p.MaxWait
+}
+
+// backoff 第 attempt 次重试（从 0 开始）的默认退避时长
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := defaultRetryBaseBackoff << uint(attempt)
+	if d <= 0 || d > p.maxWait() {
+		return p.maxWait()
+	}
+	return d
+}
+
+// ParseRetryAfter 解析 Retry-After：支持秒数与 HTTP-date 两种形式
+// 值非法或为空时 ok 为 false；时间已过时返回 0
+func ParseRetryAfter(value string, now time.Time) (d time.Duration, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d = t.Sub(now); d < 0 {
+		return 0, true
+	}
+	return d, true
+}
+
+// retryable 判断本次结果是否值得重试
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Method == http.MethodGet || req.Method == http.MethodHead
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// retryWait 计算下次重试前的等待时长：优先使用服务端 Retry-After，否则指数退避；均受 MaxWait 限制
+func (p RetryPolicy) retryWait(attempt int, resp *http.Response) time.Duration {
+	wait := p.backoff(attempt)
+	if resp != nil {
+		if d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			wait = d
+		}
+	}
+	if wait > p.maxWait() {
+		wait = p.maxWait()
+	}
+	return wait
+}
+
+// rewindRequest 返回可重新发送的请求副本；请求体无法重放时 ok 为 false
+func rewindRequest(ctx context.Context, req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Clone(ctx), true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next := req.Clone(ctx)
+	next.Body = body
+	return next, true
+}
+
+// sleepContext 等待 d，ctx 结束时提前返回其错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// drainAndClose 读尽并关闭响应体，以便连接复用
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+}
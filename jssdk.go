@@ -0,0 +1,69 @@
+package wxgo
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// nonceAlphabet 随机串字符集
+	nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// nonceLength 默认随机串长度
+	nonceLength = 16
+)
+
+// JSConfig 网页调用 wx.config 所需的签名参数
+type JSConfig struct {
+	AppID     string `json:"appId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	Signature string `json:"signature"`
+}
+
+// SignJSConfig 使用给定的 jsapi_ticket 为页面 URL 生成 wx.config 签名
+// URL 中 # 及之后的部分按微信要求去掉；时间戳取自 Config.Clock，随机串取自 Config.NonceFunc，二者注入后签名结果确定
+func (c *Client) SignJSConfig(ticket, pageURL string) (*JSConfig, Code, error) {
+	if ticket == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("jsapi_ticket is required")
+	}
+	if pageURL == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("url is required")
+	}
+	if i := strings.IndexByte(pageURL, '#'); i >= 0 {
+		pageURL = pageURL[:i]
+	}
+
+	cfg := &JSConfig{
+		AppID:     c.cfg.AppID,
+		Timestamp: c.now().Unix(),
+		NonceStr:  c.nonce(),
+	}
+	plain := fmt.Sprintf("jsapi_ticket=%s&noncestr=%s&timestamp=%d&url=%s", ticket, cfg.NonceStr, cfg.Timestamp, pageURL)
+	sum := sha1.Sum([]byte(plain))
+	cfg.Signature = hex.EncodeToString(sum[:])
+	return cfg, CodeOK, nil
+}
+
+// nonce 返回签名用的随机串，优先使用配置的 NonceFunc
+func (c *Client) nonce() string {
+	if c.cfg.NonceFunc != nil {
+		return c.cfg.NonceFunc()
+	}
+	return randomNonce()
+}
+
+// randomNonce 生成 16 位字母数字随机串（crypto/rand）
+func randomNonce() string {
+	buf := make([]byte, nonceLength)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand 在受支持的平台上不会失败
+		panic(fmt.Sprintf("wxgo: generate nonce: %v", err))
+	}
+	for i, b := range buf {
+		buf[i] = nonceAlphabet[int(b)%len(nonceAlphabet)]
+	}
+	return string(buf)
+}
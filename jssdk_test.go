@@ -0,0 +1,36 @@
+package wxgo_test
+
+import (
+	"testing"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+func TestSignJSConfigUsesValidatedAppID(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  wxgo.Config
+		want string
+	}{
+		{"trimmed app id", wxgo.Config{AppID: " wx_trim\n", AppSecret: " secret\t"}, "wx_trim"},
+		{"work wechat corp id", wxgo.Config{Provider: wxgo.ProviderWorkWeChat, CorpID: " ww_corp ", CorpSecret: "secret", AgentID: "1000002"}, "ww_corp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Mock = true
+			c, err := wxgo.NewClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer c.Close()
+
+			js, _, err := c.SignJSConfig("ticket", "https://example.com/page")
+			if err != nil {
+				t.Fatalf("SignJSConfig: %v", err)
+			}
+			if js.AppID != tt.want {
+				t.Fatalf("appId = %q, want %q", js.AppID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package wxgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+const (
+	materialAddPath      = "/cgi-bin/material/add_material"
+	materialGetPath      = "/cgi-bin/material/get_material"
+	materialDelPath      = "/cgi-bin/material/del_material"
+	materialBatchGetPath = "/cgi-bin/material/batchget_material"
+
+	// maxMaterialBatchCount 批量获取素材单页最大数量
+	maxMaterialBatchCount = 20
+)
+
+// MaterialType 永久素材类型
+type MaterialType string
+
+const (
+	// MaterialImage 图片
+	MaterialImage MaterialType = "image"
+	// MaterialVoice 语音
+	MaterialVoice MaterialType = "voice"
+	// MaterialVideo 视频（上传需附带 VideoDescription）
+	MaterialVideo MaterialType = "video"
+	// MaterialThumb 缩略图
+	MaterialThumb MaterialType = "thumb"
+	// MaterialNews 图文（仅用于 BatchGetMaterial）
+	MaterialNews MaterialType = "news"
+)
+
+// MaterialResult 新增永久素材的返回结果
+type MaterialResult struct {
+	// MediaID 永久素材 media_id
+	MediaID string `json:"media_id"`
+	// URL 图片素材的访问地址（仅图片返回）
+	URL string `json:"url"`
+}
+
+// VideoDescription 视频素材的描述信息
+type VideoDescription struct {
+	Title        string `json:"title"`
+	Introduction string `json:"introduction"`
+}
+
+// Material 获取到的永久素材
+// 图片/语音/缩略图返回二进制（Content、ContentType）；视频返回 Title/Description/DownURL；图文返回 NewsItems
+type Material struct {
+	Content     []byte
+	ContentType string
+
+	Title       string
+	Description string
+	DownURL     string
+
+	NewsItems []MaterialNewsItem
+}
+
+// MaterialNewsItem 图文素材中的单篇文章
+type MaterialNewsItem struct {
+	Title            string `json:"title"`
+	ThumbMediaID     string `json:"thumb_media_id"`
+	Author           string `json:"author"`
+	Digest           string `json:"digest"`
+	Content          string `json:"content"`
+	URL              string `json:"url"`
+	ContentSourceURL string `json:"content_source_url"`
+}
+
+// MaterialPage 批量获取素材的分页结果
+type MaterialPage struct {
+	TotalCount int            `json:"total_count"`
+	ItemCount  int            `json:"item_count"`
+	Items      []MaterialItem `json:"item"`
+}
+
+// MaterialItem 素材列表中的单项
+type MaterialItem struct {
+	MediaID    string `json:"media_id"`
+	Name       string `json:"name"`
+	UpdateTime int64  `json:"update_time"`
+	URL        string `json:"url"`
+	// Content 图文素材内容（仅 news 类型）
+	Content *struct {
+		NewsItems []MaterialNewsItem `json:"news_item"`
+	} `json:"content,omitempty"`
+}
+
+// AddPermanentMaterial 新增永久素材（图片/语音/缩略图）
+// 视频素材需附带描述，请使用 AddPermanentVideo
+func (c *Client) AddPermanentMaterial(ctx context.Context, mediaType MaterialType, filename string, r io.Reader) (*MaterialResult, Code, error) {
+	return c.AddPermanentMaterialWithProgress(ctx, mediaType, filename, r, nil)
+}
+
+// AddPermanentMaterialWithProgress 同 AddPermanentMaterial，上传过程中通过 onProgress 报告进度，规则见 AddPermanentVideoWithProgress
+func (c *Client) AddPermanentMaterialWithProgress(ctx context.Context, mediaType MaterialType, filename string, r io.Reader, onProgress func(sent, total int64)) (*MaterialResult, Code, error) {
+	switch mediaType {
+	case MaterialImage, MaterialVoice, MaterialThumb:
+	case MaterialVideo:
+		return nil, CodeInvalidParam, fmt.Errorf("video material requires description, use AddPermanentVideo")
+	default:
+		return nil, CodeInvalidParam, fmt.Errorf("unsupported material type: %q", mediaType)
+	}
+	return c.addMaterial(ctx, mediaType, filename, r, nil, onProgress)
+}
+
+// AddPermanentVideo 新增永久视频素材，desc 以 description 字段随 multipart 一起提交
+func (c *Client) AddPermanentVideo(ctx context.Context, filename string, r io.Reader, desc VideoDescription) (*MaterialResult, Code, error) {
+	return c.AddPermanentVideoWithProgress(ctx, filename, r, desc, nil)
+}
+
+// AddPermanentVideoWithProgress 同 AddPermanentVideo，适合命令行/后台工具上传大文件时展示进度
+// 素材内容发送时流式读取；每次读出数据后调用 onProgress(sent, total)，sent 为已发送的素材字节数（不含 multipart 头尾），
+// total 取自 r：*os.File 取文件大小，其余 io.Seeker 通过 Seek 计算，否则为 -1（以 chunked 方式发送）。
+// onProgress 在发送请求的 goroutine 中同步调用，应尽快返回；为 nil 时不报告进度。
+// 可 Seek 的 r 在 token 失效重试等需要重发时回到起始位置重新读取，sent 从 0 重新计数；
+// 不可 Seek 的 r 只能发送一次，需要重发时返回错误
+func (c *Client) AddPermanentVideoWithProgress(ctx context.Context, filename string, r io.Reader, desc VideoDescription, onProgress func(sent, total int64)) (*MaterialResult, Code, error) {
+	if desc.Title == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("video title is required")
+	}
+	return c.addMaterial(ctx, MaterialVideo, filename, r, &desc, onProgress)
+}
+
+func (c *Client) addMaterial(ctx context.Context, mediaType MaterialType, filename string, r io.Reader, desc *VideoDescription, onProgress func(sent, total int64)) (*MaterialResult, Code, error) {
+	if filename == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("filename is required")
+	}
+	if r == nil {
+		return nil, CodeInvalidParam, fmt.Errorf("material reader is required")
+	}
+
+	var fields map[string]string
+	if desc != nil {
+		raw, err := json.Marshal(desc)
+		if err != nil {
+			return nil, CodeUnknown, fmt.Errorf("marshal video description: %w", err)
+		}
+		fields = map[string]string{"description": string(raw)}
+	}
+	content, err := newUploadContent(r, onProgress)
+	if err != nil {
+		return nil, CodeUnknown, err
+	}
+	body, contentType, err := newMultipartUpload("media", filename, content, fields)
+	if err != nil {
+		return nil, CodeUnknown, err
+	}
+
+	query := url.Values{}
+	query.Set("type", string(mediaType))
+
+	resp, code, err := c.doUploadAPI(ctx, materialAddPath, query, contentType, body)
+	if err != nil {
+		return nil, code, err
+	}
+
+	var result MaterialResult
+	if code, err := decodeAPIResponse(materialAddPath, resp.body, &result); err != nil {
+		return nil, code, err
+	}
+	return &result, code, nil
+}
+
+// GetPermanentMaterial 获取永久素材
+// 微信对图片等返回二进制、对视频/图文返回 JSON，此处按响应内容区分
+func (c *Client) GetPermanentMaterial(ctx context.Context, mediaID string) (*Material, Code, error) {
+	if mediaID == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("media_id is required")
+	}
+
+	raw, err := json.Marshal(map[string]any{"media_id": mediaID})
+	if err != nil {
+		return nil, CodeUnknown, fmt.Errorf("marshal %s request: %w", materialGetPath, err)
+	}
+
+	resp, code, err := c.doMediaAPI(ctx, http.MethodPost, materialGetPath, nil, "application/json", raw)
+	if err != nil {
+		return nil, code, err
+	}
+
+	contentType := resp.header.Get("Content-Type")
+	if !isJSONResponse(contentType, resp.body) {
+		return &Material{Content: resp.body, ContentType: contentType}, code, nil
+	}
+
+	var apiResp struct {
+		Title       string             `json:"title"`
+		Description string             `json:"description"`
+		DownURL     string             `json:"down_url"`
+		NewsItems   []MaterialNewsItem `json:"news_item"`
+	}
+	if code, err := decodeAPIResponse(materialGetPath, resp.body, &apiResp); err != nil {
+		return nil, code, err
+	}
+
+	return &Material{
+		Title:       apiResp.Title,
+		Description: apiResp.Description,
+		DownURL:     apiResp.DownURL,
+		NewsItems:   apiResp.NewsItems,
+	}, code, nil
+}
+
+// DeletePermanentMaterial 删除永久素材
+func (c *Client) DeletePermanentMaterial(ctx context.Context, mediaID string) (Code, error) {
+	if mediaID == "" {
+		return CodeInvalidParam, fmt.Errorf("media_id is required")
+	}
+	return c.postJSON(ctx, materialDelPath, map[string]any{"media_id": mediaID}, nil)
+}
+
+// BatchGetMaterial 分页获取永久素材列表，count 取值 1~20
+func (c *Client) BatchGetMaterial(ctx context.Context, mediaType MaterialType, offset, count int) (*MaterialPage, Code, error) {
+	if mediaType == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("material type is required")
+	}
+	if offset < 0 {
+		return nil, CodeInvalidParam, fmt.Errorf("offset must be >=0")
+	}
+	if count < 1 || count > maxMaterialBatchCount {
+		return nil, CodeInvalidParam, fmt.Errorf("count must be in [1,%d]", maxMaterialBatchCount)
+	}
+
+	body := map[string]any{
+		"type":   mediaType,
+		"offset": offset,
+		"count":  count,
+	}
+	var page MaterialPage
+	if code, err := c.postJSON(ctx, materialBatchGetPath, body, &page); err != nil {
+		return nil, code, err
+	}
+	return &page, CodeOK, nil
+}
+
+// isJSONResponse 判断响应是否为 JSON（微信的错误/视频/图文响应），而非二进制文件
+func isJSONResponse(contentType string, data []byte) bool {
+	if strings.Contains(contentType, "json") {
+		return true
+	}
+	trimmed := transport.TrimJSONBody(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
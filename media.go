@@ -0,0 +1,65 @@
+package wxgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const mediaGetPath = "/cgi-bin/media/get"
+
+// TempMedia 获取到的临时素材
+// 图片/语音/缩略图返回二进制（Bytes、ContentType）；视频微信不返回文件，而是返回 VideoURL（无需 access_token 的 CDN 地址）
+type TempMedia struct {
+	Bytes       []byte
+	ContentType string
+
+	VideoURL string
+}
+
+// IsVideo 是否为视频素材（仅有 VideoURL）
+func (m *TempMedia) IsVideo() bool {
+	return m.VideoURL != ""
+}
+
+// GetTempMedia 获取临时素材（media/get），响应体受 MaxMediaBytes 限制
+// 微信对视频返回 JSON {"video_url": ...} 而非文件，此时 Bytes 为空、VideoURL 为下载地址
+func (c *Client) GetTempMedia(ctx context.Context, mediaID string) (*TempMedia, Code, error) {
+	if mediaID == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("media_id is required")
+	}
+
+	resp, code, err := c.doMediaAPI(ctx, http.MethodGet, mediaGetPath, url.Values{"media_id": {mediaID}}, "", nil)
+	if err != nil {
+		return nil, code, err
+	}
+
+	contentType := resp.header.Get("Content-Type")
+	if !isJSONResponse(contentType, resp.body) {
+		return &TempMedia{Bytes: resp.body, ContentType: contentType}, code, nil
+	}
+
+	var apiResp struct {
+		VideoURL string `json:"video_url"`
+	}
+	if code, err := decodeAPIResponse(mediaGetPath, resp.body, &apiResp); err != nil {
+		return nil, code, err
+	}
+	if apiResp.VideoURL == "" {
+		return nil, CodeInvalidResponse, fmt.Errorf("decode %s response: missing video_url", mediaGetPath)
+	}
+	return &TempMedia{VideoURL: apiResp.VideoURL}, code, nil
+}
+
+// GetTempMediaVideoURL 获取临时视频素材的下载地址；media_id 不是视频时返回 CodeInvalidParam
+func (c *Client) GetTempMediaVideoURL(ctx context.Context, mediaID string) (string, Code, error) {
+	media, code, err := c.GetTempMedia(ctx, mediaID)
+	if err != nil {
+		return "", code, err
+	}
+	if !media.IsVideo() {
+		return "", CodeInvalidParam, fmt.Errorf("media %s is not a video (content-type %q)", mediaID, media.ContentType)
+	}
+	return media.VideoURL, code, nil
+}
@@ -0,0 +1,251 @@
+package wxgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	menuCreatePath            = "/cgi-bin/menu/create"
+	menuDeletePath            = "/cgi-bin/menu/delete"
+	menuAddConditionalPath    = "/cgi-bin/menu/addconditional"
+	menuDeleteConditionalPath = "/cgi-bin/menu/delconditional"
+	menuTryMatchPath          = "/cgi-bin/menu/trymatch"
+
+	// maxMenuButtons 一级菜单最多个数
+	maxMenuButtons = 3
+	// maxMenuSubButtons 每个一级菜单下二级菜单最多个数
+	maxMenuSubButtons = 5
+	// maxMenuNameBytes 一级菜单标题最大字节数（UTF-8，约 5 个汉字）
+	maxMenuNameBytes = 16
+	// maxMenuSubNameBytes 二级菜单标题最大字节数
+	maxMenuSubNameBytes = 60
+	// maxMenuKeyBytes 菜单 key 最大字节数
+	maxMenuKeyBytes = 128
+	// maxMenuURLBytes 网页链接最大字节数
+	maxMenuURLBytes = 1024
+)
+
+// menuButtonRequired 各按钮类型必填的字段
+var menuButtonRequired = map[string][]string{
+	"click":                {"key"},
+	"scancode_push":        {"key"},
+	"scancode_waitmsg":     {"key"},
+	"pic_sysphoto":         {"key"},
+	"pic_photo_or_album":   {"key"},
+	"pic_weixin":           {"key"},
+	"location_select":      {"key"},
+	"view":                 {"url"},
+	"miniprogram":          {"url", "appid", "pagepath"},
+	"media_id":             {"media_id"},
+	"view_limited":         {"media_id"},
+	"article_id":           {"article_id"},
+	"article_view_limited": {"article_id"},
+}
+
+// Menu 自定义菜单
+type Menu struct {
+	// Buttons 一级菜单
+	Buttons []MenuButton `json:"button"`
+}
+
+// MenuButton 菜单按钮；含 SubButtons 时为一级菜单，Type 等字段可不填
+type MenuButton struct {
+	// Type 按钮类型：click、view、miniprogram、scancode_push、media_id、article_id 等
+	Type string `json:"type,omitempty"`
+	// Name 按钮标题
+	Name string `json:"name"`
+	// Key click 等事件类型的 key
+	Key string `json:"key,omitempty"`
+	// URL view 类型的跳转地址；miniprogram 类型的备用网页
+	URL string `json:"url,omitempty"`
+	// MediaID media_id/view_limited 类型的永久素材 ID
+	MediaID string `json:"media_id,omitempty"`
+	// AppID miniprogram 类型的小程序 AppID
+	AppID string `json:"appid,omitempty"`
+	// PagePath miniprogram 类型的小程序页面路径
+	PagePath string `json:"pagepath,omitempty"`
+	// ArticleID article_id/article_view_limited 类型的发布文章 ID
+	ArticleID string `json:"article_id,omitempty"`
+	// SubButtons 二级菜单
+	SubButtons []MenuButton `json:"sub_button,omitempty"`
+}
+
+// MatchRule 个性化菜单的匹配规则，至少设置一个字段
+type MatchRule struct {
+	// TagID 用户标签 ID
+	TagID string `json:"tag_id,omitempty"`
+	// Sex 性别：1 男，2 女（微信已不再返回用户性别，该规则可能不生效）
+	Sex string `json:"sex,omitempty"`
+	// Country 国家
+	Country string `json:"country,omitempty"`
+	// Province 省份
+	Province string `json:"province,omitempty"`
+	// City 城市
+	City string `json:"city,omitempty"`
+	// ClientPlatformType 客户端系统：1 iOS，2 Android，3 其他
+	ClientPlatformType string `json:"client_platform_type,omitempty"`
+	// Language 语言，如 zh_CN、en
+	Language string `json:"language,omitempty"`
+}
+
+// isEmpty 是否未设置任何匹配字段
+func (r MatchRule) isEmpty() bool {
+	return r == MatchRule{}
+}
+
+// ValidateMenu 按微信规则校验菜单，错误信息指出具体按钮与字段（如 button[1].sub_button[0].key）
+// 规则：一级菜单 1~3 个，每个最多 5 个二级菜单且不再嵌套；标题必填，一级不超过 16 字节、二级不超过 60 字节（UTF-8，汉字占 3 字节）；
+// 含二级菜单的一级菜单不设 type，其余按钮须为已知类型并填写该类型的必填字段：
+// click 等事件类型需 key（≤128 字节），view 需 url（≤1024 字节），miniprogram 需 url、appid 与 pagepath，
+// media_id/view_limited 需 media_id，article_id/article_view_limited 需 article_id
+func ValidateMenu(menu Menu) error {
+	if len(menu.Buttons) == 0 {
+		return fmt.Errorf("menu buttons are required")
+	}
+	if len(menu.Buttons) > maxMenuButtons {
+		return fmt.Errorf("menu has %d buttons, at most %d top-level buttons are allowed", len(menu.Buttons), maxMenuButtons)
+	}
+
+	for i, btn := range menu.Buttons {
+		path := fmt.Sprintf("button[%d]", i)
+		if err := validateMenuName(path, btn.Name, maxMenuNameBytes); err != nil {
+			return err
+		}
+		if len(btn.SubButtons) == 0 {
+			if err := validateMenuAction(path, btn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if btn.Type != "" {
+			return fmt.Errorf("%s.type: must be empty when sub_button is set, got %q", path, btn.Type)
+		}
+		if len(btn.SubButtons) > maxMenuSubButtons {
+			return fmt.Errorf("%s.sub_button: has %d buttons, at most %d are allowed", path, len(btn.SubButtons), maxMenuSubButtons)
+		}
+		for j, sub := range btn.SubButtons {
+			subPath := fmt.Sprintf("%s.sub_button[%d]", path, j)
+			if len(sub.SubButtons) > 0 {
+				return fmt.Errorf("%s.sub_button: menus support only two levels", subPath)
+			}
+			if err := validateMenuName(subPath, sub.Name, maxMenuSubNameBytes); err != nil {
+				return err
+			}
+			if err := validateMenuAction(subPath, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateMenuName 校验按钮标题非空且不超过 limit 字节
+func validateMenuName(path, name string, limit int) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%s.name: is required", path)
+	}
+	if len(name) > limit {
+		return fmt.Errorf("%s.name: %q is %d bytes, must be <=%d bytes in UTF-8", path, name, len(name), limit)
+	}
+	return nil
+}
+
+// validateMenuAction 校验叶子按钮的类型、必填字段与长度
+func validateMenuAction(path string, btn MenuButton) error {
+	if btn.Type == "" {
+		return fmt.Errorf("%s.type: is required for a button without sub_button", path)
+	}
+	required, ok := menuButtonRequired[btn.Type]
+	if !ok {
+		return fmt.Errorf("%s.type: unknown button type %q", path, btn.Type)
+	}
+
+	values := map[string]string{
+		"key":        btn.Key,
+		"url":        btn.URL,
+		"appid":      btn.AppID,
+		"pagepath":   btn.PagePath,
+		"media_id":   btn.MediaID,
+		"article_id": btn.ArticleID,
+	}
+	for _, field := range required {
+		if values[field] == "" {
+			return fmt.Errorf("%s.%s: is required for type %q", path, field, btn.Type)
+		}
+	}
+	if len(btn.Key) > maxMenuKeyBytes {
+		return fmt.Errorf("%s.key: is %d bytes, must be <=%d", path, len(btn.Key), maxMenuKeyBytes)
+	}
+	if len(btn.URL) > maxMenuURLBytes {
+		return fmt.Errorf("%s.url: is %d bytes, must be <=%d", path, len(btn.URL), maxMenuURLBytes)
+	}
+	return nil
+}
+
+// CreateMenu 创建自定义菜单（覆盖现有默认菜单）；提交前以 ValidateMenu 校验，不合法时返回 CodeInvalidParam
+func (c *Client) CreateMenu(ctx context.Context, menu Menu) (Code, error) {
+	if err := ValidateMenu(menu); err != nil {
+		return CodeInvalidParam, err
+	}
+	return c.postJSON(ctx, menuCreatePath, menu, nil)
+}
+
+// DeleteMenu 删除自定义菜单，同时删除全部个性化菜单
+func (c *Client) DeleteMenu(ctx context.Context) (Code, error) {
+	return c.getJSON(ctx, menuDeletePath, nil, nil)
+}
+
+// AddConditionalMenu 创建个性化菜单，返回 menuid
+// 需已存在默认菜单；matchRule 至少设置一个匹配字段；menu 的校验同 CreateMenu
+func (c *Client) AddConditionalMenu(ctx context.Context, menu Menu, matchRule MatchRule) (int64, Code, error) {
+	if err := ValidateMenu(menu); err != nil {
+		return 0, CodeInvalidParam, err
+	}
+	if matchRule.isEmpty() {
+		return 0, CodeInvalidParam, fmt.Errorf("match rule requires at least one field")
+	}
+
+	body := map[string]any{
+		"button":    menu.Buttons,
+		"matchrule": matchRule,
+	}
+	var apiResp struct {
+		MenuID json.RawMessage `json:"menuid"`
+	}
+	if code, err := c.postJSON(ctx, menuAddConditionalPath, body, &apiResp); err != nil {
+		return 0, code, err
+	}
+
+	// 微信以字符串返回 menuid，兼容数字形式
+	menuID, err := strconv.ParseInt(strings.Trim(string(apiResp.MenuID), `"`), 10, 64)
+	if err != nil {
+		return 0, CodeInvalidResponse, fmt.Errorf("parse menuid %s: %w", apiResp.MenuID, err)
+	}
+	return menuID, CodeOK, nil
+}
+
+// DeleteConditionalMenu 删除个性化菜单
+func (c *Client) DeleteConditionalMenu(ctx context.Context, menuID int64) (Code, error) {
+	if menuID <= 0 {
+		return CodeInvalidParam, fmt.Errorf("menuid is required")
+	}
+	body := map[string]any{"menuid": strconv.FormatInt(menuID, 10)}
+	return c.postJSON(ctx, menuDeleteConditionalPath, body, nil)
+}
+
+// TryMatchMenu 测试个性化菜单匹配结果，userID 可为粉丝的 openid 或微信号
+func (c *Client) TryMatchMenu(ctx context.Context, userID string) (*Menu, Code, error) {
+	if userID == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("user_id is required")
+	}
+	var menu Menu
+	if code, err := c.postJSON(ctx, menuTryMatchPath, map[string]any{"user_id": userID}, &menu); err != nil {
+		return nil, code, err
+	}
+	return &menu, CodeOK, nil
+}
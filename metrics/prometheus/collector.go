@@ -0,0 +1,130 @@
+// Package prometheus 提供 wxgo.Metrics 的 Prometheus 实现
+// 独立 module，仅在引入本包时才依赖 prometheus/client_golang
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qingfeng-studio/wxgo"
+)
+
+// Collector 实现 wxgo.Metrics，将 token 相关指标注册到 Prometheus
+type Collector struct {
+	fetchTotal    *prometheus.CounterVec
+	fetchDuration prometheus.Histogram
+	cacheHits     prometheus.Counter
+	cacheMisses   prometheus.Counter
+	lockWait      *prometheus.HistogramVec
+	lockHeld      prometheus.Histogram
+	lockOverruns  prometheus.Counter
+
+	reg        prometheus.Registerer
+	collectors []prometheus.Collector
+}
+
+var (
+	_ wxgo.Metrics          = (*Collector)(nil)
+	_ wxgo.LockHoldObserver = (*Collector)(nil)
+)
+
+// NewCollector 创建并注册指标；reg 为 nil 时使用 prometheus.DefaultRegisterer
+// 注册的指标：
+//   - wxgo_token_fetch_total{code}
+//   - wxgo_token_fetch_duration_seconds
+//   - wxgo_token_cache_hits_total
+//   - wxgo_token_cache_misses_total
+//   - wxgo_lock_wait_seconds{result}
+//   - wxgo_lock_held_seconds
+//   - wxgo_lock_overruns_total
+func NewCollector(reg prometheus.Registerer) (*Collector, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	c := &Collector{
+		fetchTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "wxgo_token_fetch_total",
+			Help: "Number of access token fetches from WeChat, by result code.",
+		}, []string{"code"}),
+		fetchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "wxgo_token_fetch_duration_seconds",
+			Help:    "Duration of access token fetches from WeChat.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "wxgo_token_cache_hits_total",
+			Help: "Number of access token lookups served from cache.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "wxgo_token_cache_misses_total",
+			Help: "Number of access token lookups that missed the cache or found an expired token.",
+		}),
+		lockWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "wxgo_lock_wait_seconds",
+			Help:    "Time spent acquiring the distributed token lock.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"result"}),
+		lockHeld: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "wxgo_lock_held_seconds",
+			Help:    "Time the distributed token lock was held.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		lockOverruns: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "wxgo_lock_overruns_total",
+			Help: "Number of times the distributed token lock was held longer than its TTL.",
+		}),
+		reg: reg,
+	}
+	c.collectors = []prometheus.Collector{c.fetchTotal, c.fetchDuration, c.cacheHits, c.cacheMisses, c.lockWait, c.lockHeld, c.lockOverruns}
+
+	for i, col := range c.collectors {
+		if err := reg.Register(col); err != nil {
+			for _, registered := range c.collectors[:i] {
+				reg.Unregister(registered)
+			}
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// CacheHit 实现 wxgo.Metrics
+func (c *Collector) CacheHit() {
+	c.cacheHits.Inc()
+}
+
+// CacheMiss 实现 wxgo.Metrics
+func (c *Collector) CacheMiss() {
+	c.cacheMisses.Inc()
+}
+
+// TokenFetch 实现 wxgo.Metrics
+func (c *Collector) TokenFetch(code wxgo.Code, duration time.Duration) {
+	c.fetchTotal.WithLabelValues(string(code)).Inc()
+	c.fetchDuration.Observe(duration.Seconds())
+}
+
+// LockAcquire 实现 wxgo.Metrics
+func (c *Collector) LockAcquire(wait time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	c.lockWait.WithLabelValues(result).Observe(wait.Seconds())
+}
+
+// LockHeld 实现 wxgo.LockHoldObserver
+func (c *Collector) LockHeld(held time.Duration, overrun bool) {
+	c.lockHeld.Observe(held.Seconds())
+	if overrun {
+		c.lockOverruns.Inc()
+	}
+}
+
+// Unregister 从 Registerer 注销全部指标
+func (c *Collector) Unregister() {
+	for _, col := range c.collectors {
+		c.reg.Unregister(col)
+	}
+}
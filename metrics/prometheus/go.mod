@@ -0,0 +1,24 @@
+module github.com/qingfeng-studio/wxgo/metrics/prometheus
+
+go 1.22
+
+require (
+	github.com/prometheus/client_golang v1.19.0
+	github.com/qingfeng-studio/wxgo v0.0.0-20261017020631-d4c23ab1b533
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-redis/redis/v8 v8.11.5 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/common v0.48.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	golang.org/x/sync v0.7.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	google.golang.org/protobuf v1.32.0 // indirect
+)
+
+// 与仓库根目录的 wxgo 一同开发与构建；发布后由使用方按 require 的版本解析
+replace github.com/qingfeng-studio/wxgo => ../..
@@ -0,0 +1,20 @@
+package wxgo
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// VerifyUserDataSignature 校验小程序 getUserInfo 返回的 signature，检测 rawData 是否被篡改
+// 按微信官方算法计算 sha1(rawData + sessionKey) 并做常量时间比较，不一致返回 false
+// 该校验只保证 rawData 完整性，与 encryptedData 解密后的 watermark（appid/时间戳）校验相互独立
+func VerifyUserDataSignature(rawData, sessionKey, signature string) bool {
+	if sessionKey == "" || signature == "" {
+		return false
+	}
+	sum := sha1.Sum([]byte(rawData + sessionKey))
+	expected := hex.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signature))) == 1
+}
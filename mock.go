@@ -0,0 +1,90 @@
+package wxgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// MockAccessToken Mock 模式下微信返回的 access_token
+	MockAccessToken = "mock_access_token"
+	// MockTicket Mock 模式下 ticket/getticket 返回的 ticket（jsapi_ticket 等）
+	MockTicket = "mock_ticket"
+	// MockQRCodeTicket Mock 模式下生成二维码返回的 ticket
+	MockQRCodeTicket = "mock_qrcode_ticket"
+	// MockQRCodeURL Mock 模式下生成二维码返回的 url
+	MockQRCodeURL = "http://weixin.qq.com/q/mock"
+
+	// mockTokenExpiresIn Mock token 的有效期（秒），与微信一致
+	mockTokenExpiresIn = 7200
+)
+
+// mockQRCodeImage Mock 模式下下载二维码返回的图片内容（PNG 文件头，仅用于占位）
+var mockQRCodeImage = []byte("\x89PNG\r\n\x1a\n")
+
+// mockTransport 替换 HTTP 层的假微信服务，不发起任何网络请求
+// 只替换传输层：缓存、锁、token 刷新等逻辑仍按真实路径执行
+type mockTransport struct{}
+
+// RoundTrip 按请求路径返回固定响应；未识别的接口返回 errcode=0
+func (mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	path := req.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/cgi-bin/token"), strings.HasSuffix(path, "/cgi-bin/stable_token"):
+		return mockJSON(req, map[string]any{
+			"access_token": MockAccessToken,
+			"expires_in":   mockTokenExpiresIn,
+		})
+	case strings.HasSuffix(path, "/cgi-bin/component/api_component_token"):
+		return mockJSON(req, map[string]any{
+			"component_access_token": MockAccessToken,
+			"expires_in":             mockTokenExpiresIn,
+		})
+	case strings.HasSuffix(path, ticketGetPath):
+		return mockJSON(req, map[string]any{
+			"errcode":    0,
+			"errmsg":     "ok",
+			"ticket":     MockTicket,
+			"expires_in": mockTokenExpiresIn,
+		})
+	case strings.HasSuffix(path, qrCodeCreatePath):
+		return mockJSON(req, map[string]any{
+			"ticket":         MockQRCodeTicket,
+			"expire_seconds": 0,
+			"url":            MockQRCodeURL,
+		})
+	case strings.HasSuffix(path, "/cgi-bin/showqrcode"):
+		return mockResponse(req, "image/png", mockQRCodeImage), nil
+	default:
+		return mockJSON(req, map[string]any{"errcode": 0, "errmsg": "ok"})
+	}
+}
+
+func mockJSON(req *http.Request, v any) (*http.Response, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return mockResponse(req, "application/json; charset=utf-8", data), nil
+}
+
+func mockResponse(req *http.Request, contentType string, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
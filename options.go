@@ -0,0 +1,149 @@
+package wxgo
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingfeng-studio/wxgo/internal/token"
+)
+
+// Option 客户端可选配置，配合 New 使用
+type Option func(*Config)
+
+// New 以函数式选项创建微信客户端，内部构建 Config 后调用 NewClient
+func New(appID, appSecret string, opts ...Option) (*Client, error) {
+	cfg := Config{
+		AppID:     appID,
+		AppSecret: appSecret,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return NewClient(cfg)
+}
+
+// WithRedis 使用 Redis 单点缓存
+func WithRedis(client *redis.Client) Option {
+	return func(c *Config) {
+		c.RedisClient = client
+	}
+}
+
+// WithRedisCluster 使用 Redis 集群缓存
+func WithRedisCluster(client *redis.ClusterClient) Option {
+	return func(c *Config) {
+		c.RedisClusterClient = client
+	}
+}
+
+// WithCache 使用自定义缓存实现（优先级最高）
+func WithCache(cache token.Cache) Option {
+	return func(c *Config) {
+		c.Cache = cache
+	}
+}
+
+// WithHTTPTimeout 设置调用微信接口的超时时间
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.HTTPTimeout = timeout
+	}
+}
+
+// WithDistLockStrategy 设置分布式锁策略
+func WithDistLockStrategy(strategy DistLockStrategy) Option {
+	return func(c *Config) {
+		c.DistLockStrategy = strategy
+	}
+}
+
+// WithKeyPrefix 设置缓存/锁 key 前缀
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.KeyPrefix = prefix
+	}
+}
+
+// WithLogger 设置日志输出
+func WithLogger(logger Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithQRCodeDedupe 开启并发相同永久二维码请求的合并
+func WithQRCodeDedupe() Option {
+	return func(c *Config) {
+		c.DedupeQRCode = true
+	}
+}
+
+// WithUserAgent 设置出站请求的 User-Agent
+func WithUserAgent(ua string) Option {
+	return func(c *Config) {
+		c.UserAgent = ua
+	}
+}
+
+// WithRequestIDFromContext 设置从 ctx 提取请求 ID 的函数，结果写入 X-Request-Id
+func WithRequestIDFromContext(fn func(ctx context.Context) string) Option {
+	return func(c *Config) {
+		c.RequestIDFunc = fn
+	}
+}
+
+// WithMock 开启 Mock 模式，不访问微信
+func WithMock() Option {
+	return func(c *Config) {
+		c.Mock = true
+	}
+}
+
+// WithRetry 开启 HTTP 层重试：最多重试 maxRetries 次，单次等待不超过 maxWait（0 为默认 10s）
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Config) {
+		c.MaxRetries = maxRetries
+		c.MaxRetryWait = maxWait
+	}
+}
+
+// WithLockWait 取分布式锁失败后最多等待 d，期间轮询缓存读取其他实例写入的 token
+func WithLockWait(d time.Duration) Option {
+	return func(c *Config) {
+		c.LockWaitTimeout = d
+	}
+}
+
+// WithEarlyRefresh 设置提前刷新窗口；d 为 0 时仅在 token 实际过期后刷新，依赖 40001 自动刷新重试兜底
+func WithEarlyRefresh(d time.Duration) Option {
+	return func(c *Config) {
+		c.EarlyRefresh = &d
+	}
+}
+
+// WithHTTPHeaders 设置附加到每个出站请求的默认 Header
+func WithHTTPHeaders(h http.Header) Option {
+	return func(c *Config) {
+		c.DefaultHeaders = h
+	}
+}
+
+// WithBackgroundRefresh 开启后台刷新：token 进入提前刷新窗口时由后台协程主动刷新，需调用 Client.Close 停止
+func WithBackgroundRefresh() Option {
+	return func(c *Config) {
+		c.BackgroundRefresh = true
+	}
+}
+
+// WithTLSConfig 设置出站 HTTPS 的 TLS 配置，如信任企业代理 CA
+// 不要在生产环境设置 InsecureSkipVerify，详见 Config.TLSConfig
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Config) {
+		c.TLSConfig = tlsConfig
+	}
+}
@@ -1,27 +1,38 @@
 package wxgo
 
 import (
-	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/qingfeng-studio/wxgo/internal/token"
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
-	qrCodeCreateAPI = "https://api.weixin.qq.com/cgi-bin/qrcode/create"
-	qrCodeShowAPI   = "https://mp.weixin.qq.com/cgi-bin/showqrcode"
+	qrCodeCreatePath = "/cgi-bin/qrcode/create"
+	qrCodeShowPath   = "/cgi-bin/showqrcode"
+
+	// qrSceneEventPrefix 未关注用户扫码关注（subscribe 事件）时 EventKey 的前缀
+	qrSceneEventPrefix = "qrscene_"
 )
 
 // QRCodeOption 公众号二维码生成参数
 type QRCodeOption struct {
 	// SceneID 数字场景值（1~100000）。不填则使用 SceneStr
 	SceneID int64
-	// SceneStr 字符串场景值（≤64 字节，推荐使用）
+	// SceneStr 字符串场景值（推荐使用）；多段数据可用 EncodeScene 打包
+	// 首尾空白会被去掉；去空白后须非空、不含控制字符，且不超过 64 字节（按 UTF-8 字节计，中文每字 3 字节），否则返回 CodeInvalidParam
 	SceneStr string
 	// ExpireSeconds 临时二维码有效期（秒，最大 30 天）。永久码忽略此值
 	ExpireSeconds int
@@ -35,25 +46,77 @@ type QRCodeOption struct {
 type QRCodeResult struct {
 	Ticket        string
 	ExpireSeconds int
-	URL           string
-	Image         []byte
-	ContentType   string
+	// ExpiresAt 临时码的过期时间，创建时按 Config.Clock 与 ExpireSeconds 计算；永久码为零值
+	ExpiresAt   time.Time
+	URL         string
+	Image       []byte
+	ContentType string
+	// DataURI 图片的 base64 data URI（如 data:image/jpeg;base64,...），可直接内嵌到页面；仅 Download 且下载成功时填充
+	DataURI string
+}
+
+// IsPermanent 是否为永久码：微信对永久码不返回 expire_seconds
+func (r *QRCodeResult) IsPermanent() bool {
+	return r.ExpireSeconds <= 0
 }
 
 // CreateQRCode 生成公众号二维码
 // 根据 Permanent 与 SceneID/SceneStr 选择 action_name，并可选直接拉取图片
+// 开启 Config.DedupeQRCode 时，并发的相同永久码请求合并为一次微信调用；
+// 合并的调用不随任一调用方的 ctx 取消，由 DefaultTimeout 限定时长，各调用方按自己的 ctx 放弃等待
 func (c *Client) CreateQRCode(ctx context.Context, opt QRCodeOption) (*QRCodeResult, Code, error) {
 	actionName, scenePayload, code, err := buildQRCodePayload(opt)
 	if err != nil {
 		return nil, code, err
 	}
 
-	tk, codeToken, err := c.token.GetAccessToken(ctx)
-	if err != nil {
-		return nil, codeToken, err
+	// 临时码每次调用都应得到新 ticket，不做合并
+	if !c.cfg.DedupeQRCode || !opt.Permanent {
+		return c.createQRCode(ctx, opt, actionName, scenePayload)
+	}
+
+	key := qrCodeDedupeKey(actionName, scenePayload, opt.Download)
+	ch := c.qrGroup.DoChan(key, func() (any, error) {
+		// 首个调用方取消或超时不应使其他等待者失败：脱离其 ctx（保留 ctx 中的值），createQRCode 内按 DefaultTimeout 限时
+		result, code, err := c.createQRCode(context.WithoutCancel(ctx), opt, actionName, scenePayload)
+		return qrCodeCall{result: result, code: code}, err
+	})
+
+	var r singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, token.ContextCode(ctx.Err()), ctx.Err()
+	case r = <-ch:
+	}
+	call := r.Val.(qrCodeCall)
+	if r.Err != nil {
+		return nil, call.code, r.Err
 	}
 
-	reqURL := qrCodeCreateAPI + "?access_token=" + url.QueryEscape(tk)
+	// 返回副本，避免多个调用方共享同一结构体
+	result := *call.result
+	return &result, call.code, nil
+}
+
+// qrCodeDedupeKey 合并请求的 key，取自实际发送的场景值（已经 normalizeSceneStr），规范化后相同的请求共享一次调用
+func qrCodeDedupeKey(actionName string, scene map[string]any, download bool) string {
+	if sceneStr, ok := scene["scene_str"].(string); ok {
+		return fmt.Sprintf("%s|%q|%t", actionName, sceneStr, download)
+	}
+	return fmt.Sprintf("%s|%v|%t", actionName, scene["scene_id"], download)
+}
+
+// qrCodeCall 合并请求的结果
+type qrCodeCall struct {
+	result *QRCodeResult
+	code   Code
+}
+
+// createQRCode 调用微信生成二维码，并按需下载图片
+func (c *Client) createQRCode(ctx context.Context, opt QRCodeOption, actionName string, scenePayload map[string]any) (*QRCodeResult, Code, error) {
+	// 覆盖创建与下载图片两次请求
+	ctx, cancel := transport.WithDefaultTimeout(ctx, c.cfg.DefaultTimeout)
+	defer cancel()
 
 	body := map[string]any{
 		"action_name": actionName,
@@ -70,41 +133,18 @@ func (c *Client) CreateQRCode(ctx context.Context, opt QRCodeOption) (*QRCodeRes
 		return nil, CodeUnknown, fmt.Errorf("marshal qrcode request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(raw))
-	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("create qrcode request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.http.Do(ctx, req)
-	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("request qrcode create: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, CodeHTTP, fmt.Errorf("wechat qrcode status: %d", resp.StatusCode)
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
+	resp, code, err := c.doAPI(ctx, http.MethodPost, qrCodeCreatePath, nil, "application/json", raw)
 	if err != nil {
-		return nil, CodeInvalidResponse, fmt.Errorf("read qrcode response: %w", err)
+		return nil, code, err
 	}
 
 	var apiResp struct {
 		Ticket        string `json:"ticket"`
 		ExpireSeconds int    `json:"expire_seconds"`
 		URL           string `json:"url"`
-		ErrCode       int    `json:"errcode"`
-		ErrMsg        string `json:"errmsg"`
-	}
-
-	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
-		return nil, CodeInvalidResponse, fmt.Errorf("decode qrcode response: %w", err)
 	}
-
-	if apiResp.ErrCode != 0 {
-		return nil, CodeAPIError, fmt.Errorf("%w: errcode=%d, errmsg=%s", token.ErrAPIError, apiResp.ErrCode, apiResp.ErrMsg)
+	if code, err := decodeAPIResponse(qrCodeCreatePath, resp.body, &apiResp); err != nil {
+		return nil, code, err
 	}
 
 	result := &QRCodeResult{
@@ -112,12 +152,15 @@ func (c *Client) CreateQRCode(ctx context.Context, opt QRCodeOption) (*QRCodeRes
 		ExpireSeconds: apiResp.ExpireSeconds,
 		URL:           apiResp.URL,
 	}
+	if !result.IsPermanent() {
+		result.ExpiresAt = c.now().Add(time.Duration(result.ExpireSeconds) * time.Second)
+	}
 
 	if !opt.Download || apiResp.Ticket == "" {
-		return result, CodeOK, nil
+		return result, code, nil
 	}
 
-	imgURL := qrCodeShowAPI + "?ticket=" + url.QueryEscape(apiResp.Ticket)
+	imgURL := c.mpBaseURL + qrCodeShowPath + "?ticket=" + url.QueryEscape(apiResp.Ticket)
 	imgReq, err := http.NewRequestWithContext(ctx, http.MethodGet, imgURL, nil)
 	if err != nil {
 		return nil, CodeHTTP, fmt.Errorf("create qrcode image request: %w", err)
@@ -125,23 +168,41 @@ func (c *Client) CreateQRCode(ctx context.Context, opt QRCodeOption) (*QRCodeRes
 
 	imgResp, err := c.http.Do(ctx, imgReq)
 	if err != nil {
-		return nil, CodeHTTP, fmt.Errorf("download qrcode image: %w", err)
+		return nil, token.RequestErrorCode(ctx, err), fmt.Errorf("download qrcode image: %w", err)
 	}
 	defer imgResp.Body.Close()
 
 	if imgResp.StatusCode < 200 || imgResp.StatusCode >= 300 {
-		return nil, CodeHTTP, fmt.Errorf("wechat qrcode image status: %d", imgResp.StatusCode)
+		c.http.Tap(imgReq, imgResp.StatusCode, nil)
+		return nil, CodeHTTP, &transport.StatusError{Target: "qrcode image", StatusCode: imgResp.StatusCode}
 	}
 
-	data, err := io.ReadAll(imgResp.Body)
+	data, err := transport.ReadBody(imgResp.Body, c.maxMediaBytes())
 	if err != nil {
-		return nil, CodeInvalidResponse, fmt.Errorf("read qrcode image: %w", err)
+		return nil, token.ReadErrorCode(ctx, err, CodeInvalidResponse), fmt.Errorf("read qrcode image: %w", err)
 	}
+	c.http.Tap(imgReq, imgResp.StatusCode, data)
 
 	result.Image = data
 	result.ContentType = imgResp.Header.Get("Content-Type")
+	result.DataURI = qrCodeDataURI(data, result.ContentType)
 
-	return result, CodeOK, nil
+	return result, code, nil
+}
+
+// qrCodeDataURI 将图片编码为 base64 data URI；响应未带 Content-Type 时按内容嗅探
+func qrCodeDataURI(data []byte, contentType string) string {
+	if len(data) == 0 {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		mediaType = http.DetectContentType(data)
+		if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+			mediaType = mediaType[:i]
+		}
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
 }
 
 func buildQRCodePayload(opt QRCodeOption) (string, map[string]any, Code, error) {
@@ -151,17 +212,18 @@ func buildQRCodePayload(opt QRCodeOption) (string, map[string]any, Code, error)
 	var scene map[string]any
 	switch {
 	case opt.SceneStr != "":
-		if len(opt.SceneStr) > 64 {
-			return "", nil, CodeUnknown, fmt.Errorf("scene_str length must be <=64")
+		sceneStr, err := normalizeSceneStr(opt.SceneStr)
+		if err != nil {
+			return "", nil, CodeInvalidParam, err
 		}
-		scene = map[string]any{"scene_str": opt.SceneStr}
+		scene = map[string]any{"scene_str": sceneStr}
 	case opt.SceneID != 0:
 		if opt.SceneID < 1 || opt.SceneID > 100000 {
-			return "", nil, CodeUnknown, fmt.Errorf("scene_id must be in [1,100000]")
+			return "", nil, CodeInvalidParam, fmt.Errorf("scene_id must be in [1,100000]")
 		}
 		scene = map[string]any{"scene_id": opt.SceneID}
 	default:
-		return "", nil, CodeUnknown, fmt.Errorf("scene is required (scene_str or scene_id)")
+		return "", nil, CodeInvalidParam, fmt.Errorf("scene is required (scene_str or scene_id)")
 	}
 
 	// 选择 action_name
@@ -175,10 +237,10 @@ func buildQRCodePayload(opt QRCodeOption) (string, map[string]any, Code, error)
 		}
 	} else {
 		if opt.ExpireSeconds <= 0 {
-			return "", nil, CodeUnknown, fmt.Errorf("expire_seconds is required for temporary qrcode")
+			return "", nil, CodeInvalidParam, fmt.Errorf("expire_seconds is required for temporary qrcode")
 		}
 		if opt.ExpireSeconds > maxExpireSeconds {
-			return "", nil, CodeUnknown, fmt.Errorf("expire_seconds must be <= %d", maxExpireSeconds)
+			return "", nil, CodeInvalidParam, fmt.Errorf("expire_seconds must be <= %d", maxExpireSeconds)
 		}
 		if isStringScene {
 			actionName = "QR_STR_SCENE"
@@ -189,3 +251,41 @@ func buildQRCodePayload(opt QRCodeOption) (string, map[string]any, Code, error)
 
 	return actionName, scene, CodeOK, nil
 }
+
+// normalizeSceneStr 去掉字符串场景值首尾空白并校验：去空白后不能为空、须为合法 UTF-8 且不含控制字符等不可打印字符、
+// 长度不超过 64 字节（按 UTF-8 字节计，中文每字占 3 字节，最多约 21 个汉字）
+func normalizeSceneStr(sceneStr string) (string, error) {
+	trimmed := strings.TrimSpace(sceneStr)
+	if trimmed == "" {
+		return "", fmt.Errorf("scene_str %q is blank after trimming whitespace", sceneStr)
+	}
+	if !utf8.ValidString(trimmed) {
+		return "", fmt.Errorf("scene_str is not valid UTF-8")
+	}
+	for i, r := range trimmed {
+		if !unicode.IsPrint(r) {
+			return "", fmt.Errorf("scene_str contains non-printable character %U at byte %d", r, i)
+		}
+	}
+	if n := len(trimmed); n > maxSceneStrLen {
+		return "", fmt.Errorf("scene_str is %d bytes (%d characters), must be <=%d bytes in UTF-8; multi-byte characters such as Chinese count 3 bytes each",
+			n, utf8.RuneCountInString(trimmed), maxSceneStrLen)
+	}
+	return trimmed, nil
+}
+
+// ParseScanScene 从扫码事件的 EventKey 还原 CreateQRCode 时使用的场景值
+// subscribe 事件的 EventKey 形如 qrscene_123，SCAN 事件则直接是场景值；两者均可传入
+// 纯数字且在 [1,100000] 内的场景值视为 SceneID，其余视为 SceneStr
+// 注意：若业务同时使用纯数字的 SceneStr，二者无法区分，建议 SceneStr 带非数字前缀
+func ParseScanScene(eventKey string) (sceneID int64, sceneStr string, ok bool) {
+	scene := strings.TrimPrefix(eventKey, qrSceneEventPrefix)
+	if scene == "" {
+		return 0, "", false
+	}
+
+	if id, err := strconv.ParseInt(scene, 10, 64); err == nil && id >= 1 && id <= 100000 {
+		return id, "", true
+	}
+	return 0, scene, true
+}
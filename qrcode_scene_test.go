@@ -0,0 +1,40 @@
+package wxgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSceneStr(t *testing.T) {
+	han := strings.Repeat("中", 21) // 63 字节
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"63 bytes multibyte", han, han, ""},
+		{"64 bytes multibyte", han + "a", han + "a", ""},
+		{"65 bytes multibyte", han + "ab", "", "65 bytes (23 characters)"},
+		{"64 bytes four-byte runes", strings.Repeat("😀", 16), strings.Repeat("😀", 16), ""},
+		{"68 bytes four-byte runes", strings.Repeat("😀", 17), "", "68 bytes (17 characters)"},
+		{"64 bytes after trimming", "  " + han + "a\t", han + "a", ""},
+		{"blank", " \t\n", "", "blank after trimming"},
+		{"control character", "scene\x00id", "", "non-printable character U+0000 at byte 5"},
+		{"invalid utf-8", "scene\xff", "", "not valid UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSceneStr(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("normalizeSceneStr = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
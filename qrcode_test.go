@@ -0,0 +1,201 @@
+package wxgo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo"
+	"github.com/qingfeng-studio/wxgo/wxtest"
+)
+
+func TestCreateQRCodeDedupeSurvivesFirstCallerCancel(t *testing.T) {
+	var creates atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			fmt.Fprint(w, `{"access_token":"tk","expires_in":7200}`)
+			return
+		}
+		creates.Add(1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"ticket":"T","url":"http://weixin.qq.com/q/x"}`)
+	}))
+	defer srv.Close()
+
+	c, err := wxgo.New("wx_qr", "secret", func(cfg *wxgo.Config) {
+		cfg.BaseURL = srv.URL
+		cfg.DedupeQRCode = true
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	opt := wxgo.QRCodeOption{Permanent: true, SceneStr: "shared"}
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := c.CreateQRCode(firstCtx, opt)
+		firstErr <- err
+	}()
+	<-started
+
+	type outcome struct {
+		result *wxgo.QRCodeResult
+		err    error
+	}
+	second := make(chan outcome, 1)
+	go func() {
+		result, _, err := c.CreateQRCode(context.Background(), opt)
+		second <- outcome{result, err}
+	}()
+	time.Sleep(50 * time.Millisecond) // 让第二个调用加入进行中的合并调用
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first caller err = %v, want context.Canceled", err)
+	}
+	close(release)
+
+	got := <-second
+	if got.err != nil {
+		t.Fatalf("second caller failed after first caller canceled: %v", got.err)
+	}
+	if got.result.Ticket != "T" {
+		t.Fatalf("ticket = %q, want T", got.result.Ticket)
+	}
+	if n := creates.Load(); n != 1 {
+		t.Fatalf("qrcode/create called %d times, want 1", n)
+	}
+}
+
+func TestCreateQRCodeDedupeUsesNormalizedScene(t *testing.T) {
+	var creates atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			fmt.Fprint(w, `{"access_token":"tk","expires_in":7200}`)
+			return
+		}
+		creates.Add(1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"ticket":"T","url":"http://weixin.qq.com/q/x"}`)
+	}))
+	defer srv.Close()
+
+	c, err := wxgo.New("wx_qr", "secret", func(cfg *wxgo.Config) {
+		cfg.BaseURL = srv.URL
+		cfg.DedupeQRCode = true
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer c.Close()
+
+	// 去掉首尾空白后相同的场景值应合并为一次调用
+	errs := make(chan error, 2)
+	go func() {
+		_, _, err := c.CreateQRCode(context.Background(), wxgo.QRCodeOption{Permanent: true, SceneStr: "shared"})
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, _, err := c.CreateQRCode(context.Background(), wxgo.QRCodeOption{Permanent: true, SceneStr: "  shared\n"})
+		errs <- err
+	}()
+	time.Sleep(50 * time.Millisecond) // 让第二个调用加入进行中的合并调用
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("CreateQRCode: %v", err)
+		}
+	}
+	if n := creates.Load(); n != 1 {
+		t.Fatalf("qrcode/create called %d times, want 1", n)
+	}
+}
+
+func TestCreateQRCodeDownloadsFromMPBaseURL(t *testing.T) {
+	jpeg := []byte("\xff\xd8\xff\xe0 qrcode image")
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			fmt.Fprint(w, `{"access_token":"tk","expires_in":7200}`)
+			return
+		}
+		fmt.Fprint(w, `{"ticket":"T+1","expire_seconds":60,"url":"http://weixin.qq.com/q/x"}`)
+	}))
+	defer api.Close()
+
+	mpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cgi-bin/showqrcode" || r.URL.Query().Get("ticket") != "T+1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(jpeg)
+	})
+	mp := httptest.NewServer(mpHandler)
+	defer mp.Close()
+	mpURLs := map[string]string{"ipv4 with port": mp.URL}
+
+	// IPv6 字面量主机：环境不支持 IPv6 时跳过该项
+	if ln, err := net.Listen("tcp6", "[::1]:0"); err == nil {
+		mp6 := &httptest.Server{Listener: ln, Config: &http.Server{Handler: mpHandler}}
+		mp6.Start()
+		defer mp6.Close()
+		mpURLs["ipv6 literal"] = mp6.URL
+	}
+
+	for name, mpURL := range mpURLs {
+		t.Run(name, func(t *testing.T) {
+			c, err := wxgo.NewClient(wxgo.Config{AppID: "wx_qr", AppSecret: "secret", BaseURL: api.URL, MPBaseURL: mpURL})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer c.Close()
+
+			res, _, err := c.CreateQRCode(context.Background(), wxgo.QRCodeOption{SceneStr: "scene", ExpireSeconds: 60, Download: true})
+			if err != nil {
+				t.Fatalf("CreateQRCode: %v", err)
+			}
+			if !bytes.Equal(res.Image, jpeg) || res.ContentType != "image/jpeg" {
+				t.Fatalf("image = %q (%s), want the MPBaseURL server's JPEG", res.Image, res.ContentType)
+			}
+			if !strings.HasPrefix(res.DataURI, "data:image/jpeg;base64,") {
+				t.Fatalf("DataURI = %q", res.DataURI)
+			}
+		})
+	}
+}
+
+func TestWxtestServesPNGForQRCodeDownload(t *testing.T) {
+	srv := wxtest.NewServer()
+	defer srv.Close()
+	c, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	res, _, err := c.CreateQRCode(context.Background(), wxgo.QRCodeOption{SceneStr: "scene", ExpireSeconds: 60, Download: true})
+	if err != nil {
+		t.Fatalf("CreateQRCode: %v", err)
+	}
+	if res.ContentType != "image/png" || !bytes.HasPrefix(res.Image, []byte("\x89PNG")) {
+		t.Fatalf("image = %q (%s), want the PNG header placeholder", res.Image, res.ContentType)
+	}
+}
@@ -0,0 +1,93 @@
+package wxgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/qingfeng-studio/wxgo/internal/transport"
+)
+
+// Registry 按 AppID 管理多个公众号/小程序客户端，适用于多租户网关
+// 未设置连接池与出站请求选项的 Client 共用同一个 HTTP 连接池；缓存 key 已按 AppID 区分，可放心共用同一 Redis
+type Registry struct {
+	mu      sync.RWMutex
+	http    *transport.Client
+	clients map[string]*Client
+}
+
+// NewRegistry 创建客户端注册表
+// 共享连接池使用默认 HTTP 超时（10s）与默认重试策略
+func NewRegistry() *Registry {
+	return &Registry{
+		http:    transport.NewClient(),
+		clients: make(map[string]*Client),
+	}
+}
+
+// Add 注册一个应用并立即创建其 Client，配置错误在此时返回；同一 AppID 重复注册报错
+// Config 设置了 Mock、Transport、TLSConfig、HTTPTimeout、MaxRetries、MaxRetryWait、DefaultHeaders、UserAgent、
+// RequestIDFunc 或 ResponseTap 时，该应用与 NewClient 一样使用独立的连接池，其余应用共用注册表的连接池
+func (r *Registry) Add(cfg Config) error {
+	appID := strings.TrimSpace(cfg.AppID)
+	if appID == "" {
+		return ErrMissingAppID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.clients[appID]; ok {
+		return fmt.Errorf("wxgo: app_id %q already registered", appID)
+	}
+
+	var client *Client
+	var err error
+	if cfg.hasHTTPOptions() {
+		client, err = NewClient(cfg)
+	} else {
+		client, err = newClient(cfg, r.http)
+	}
+	if err != nil {
+		return fmt.Errorf("create client for %q: %w", appID, err)
+	}
+	r.clients[appID] = client
+	return nil
+}
+
+// Get 按 AppID 获取已注册的 Client
+func (r *Registry) Get(appID string) (*Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	client, ok := r.clients[appID]
+	return client, ok
+}
+
+// GetAccessToken 获取指定应用的 Access Token；未注册时返回 CodeMissingAppID
+func (r *Registry) GetAccessToken(ctx context.Context, appID string) (string, Code, error) {
+	client, ok := r.Get(appID)
+	if !ok {
+		return "", CodeMissingAppID, fmt.Errorf("wxgo: app_id %q not registered", appID)
+	}
+	return client.GetAccessToken(ctx)
+}
+
+// Close 关闭并移除全部已注册的 Client（停止各自的后台刷新），并关闭共享连接池中的空闲连接；之后 Get 均返回 false
+// 各 Client 的关闭错误合并返回；与 Client.Close 相同，不会关闭 Config 中传入的 Redis 客户端等资源
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for appID, client := range r.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close client %q: %w", appID, err))
+		}
+	}
+	r.clients = make(map[string]*Client)
+	r.http.CloseIdleConnections()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package wxgo_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qingfeng-studio/wxgo"
+	"github.com/qingfeng-studio/wxgo/wxtest"
+)
+
+func TestRegistryAddHonoursMock(t *testing.T) {
+	r := wxgo.NewRegistry()
+	defer r.Close()
+
+	// 未设置 BaseURL：若 Mock 被忽略会真实请求微信
+	if err := r.Add(wxgo.Config{AppID: "wx_mock", AppSecret: "secret", Mock: true}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	tk, code, err := r.GetAccessToken(context.Background(), "wx_mock")
+	if err != nil || code != wxgo.CodeOK {
+		t.Fatalf("GetAccessToken: code=%v err=%v", code, err)
+	}
+	if tk != wxgo.MockAccessToken {
+		t.Fatalf("token = %q, want %q", tk, wxgo.MockAccessToken)
+	}
+}
+
+func TestRegistryAddHonoursHTTPOptions(t *testing.T) {
+	srv := wxtest.NewServer()
+	defer srv.Close()
+
+	var tapped atomic.Int32
+	r := wxgo.NewRegistry()
+	defer r.Close()
+
+	cfg := wxgo.Config{
+		AppID:       wxtest.DefaultAppID,
+		AppSecret:   wxtest.DefaultAppSecret,
+		BaseURL:     srv.URL,
+		ResponseTap: func(string, int, []byte) { tapped.Add(1) },
+	}
+	if err := r.Add(cfg); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, _, err := r.GetAccessToken(context.Background(), wxtest.DefaultAppID); err != nil {
+		t.Fatalf("GetAccessToken: %v", err)
+	}
+	if tapped.Load() == 0 {
+		t.Fatal("ResponseTap was not called for a registry client")
+	}
+}
@@ -0,0 +1,78 @@
+package wxgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxSceneStrLen 二维码字符串场景值的长度上限（字节）
+	maxSceneStrLen = 64
+
+	// sceneSep 场景值各部分之间的分隔符
+	sceneSep = '|'
+	// sceneEscape 转义符：部分内容中的分隔符与转义符本身前加此字符
+	sceneEscape = '\\'
+)
+
+// EncodeScene 将多段业务数据（如门店 ID、活动 ID）打包为一个字符串场景值，可直接用作 QRCodeOption.SceneStr
+// 各部分以 | 分隔，内容中的 | 与 \ 以 \ 转义，DecodeScene 可无歧义还原；编码后超过 64 字节或结果为空时报错
+func EncodeScene(parts ...string) (string, error) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("scene requires at least one part")
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte(sceneSep)
+		}
+		for j := 0; j < len(part); j++ {
+			if part[j] == sceneSep || part[j] == sceneEscape {
+				b.WriteByte(sceneEscape)
+			}
+			b.WriteByte(part[j])
+		}
+	}
+
+	scene := b.String()
+	if scene == "" {
+		return "", fmt.Errorf("encoded scene is empty")
+	}
+	if len(scene) > maxSceneStrLen {
+		return "", fmt.Errorf("encoded scene is %d bytes, must be <=%d", len(scene), maxSceneStrLen)
+	}
+	return scene, nil
+}
+
+// DecodeScene 还原 EncodeScene 打包的场景值；可配合 ParseScanScene 从扫码事件的 EventKey 中取出 sceneStr 后调用
+// 转义符出现在末尾或转义了分隔符/转义符以外的字符时报错
+func DecodeScene(sceneStr string) ([]string, error) {
+	if sceneStr == "" {
+		return nil, fmt.Errorf("scene is empty")
+	}
+
+	var (
+		parts []string
+		b     strings.Builder
+	)
+	for i := 0; i < len(sceneStr); i++ {
+		switch c := sceneStr[i]; c {
+		case sceneEscape:
+			if i+1 >= len(sceneStr) {
+				return nil, fmt.Errorf("scene ends with a dangling escape")
+			}
+			i++
+			if next := sceneStr[i]; next != sceneSep && next != sceneEscape {
+				return nil, fmt.Errorf("invalid escape %q at byte %d", next, i)
+			}
+			b.WriteByte(sceneStr[i])
+		case sceneSep:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(parts, b.String()), nil
+}
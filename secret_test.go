@@ -0,0 +1,81 @@
+package wxgo_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+func TestTokenErrorsNeverContainSecret(t *testing.T) {
+	const secret = "s3cr3t-never-logged"
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 无效的跳转地址：http.Client 返回带完整请求 URL 的 *url.Error
+		w.Header().Set("Location", "://bad")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 回显完整请求 URL 的错误页与业务错误
+		if strings.Contains(r.URL.Path, "html") {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("<html>bad gateway for " + r.URL.String() + "</html>"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":40125,"errmsg":"invalid appsecret ` + r.URL.RawQuery + `"}`))
+	}))
+	defer echo.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	refused := "http://" + ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+	}{
+		{"connection refused", refused},
+		{"bad redirect", redirect.URL},
+		{"timeout", slow.URL},
+		{"html error page", echo.URL + "/html"},
+		{"errcode echoes query", echo.URL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := wxgo.NewClient(wxgo.Config{
+				AppID:          "wx_secret",
+				AppSecret:      secret,
+				BaseURL:        tt.baseURL,
+				DefaultTimeout: 200 * time.Millisecond,
+				MaxRetryWait:   time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer c.Close()
+
+			_, _, err = c.GetAccessToken(context.Background())
+			if err == nil {
+				t.Fatal("GetAccessToken succeeded")
+			}
+			if strings.Contains(err.Error(), secret) {
+				t.Fatalf("error leaks the secret: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package wxgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	subscribeSendPath    = "/cgi-bin/message/subscribe/send"
+	subscribeBizSendPath = "/cgi-bin/message/subscribe/bizsend"
+
+	errCodeInvalidOpenID = 40003
+	errCodeUserRefused   = 43101
+)
+
+// SubscribeDataItem 订阅消息模板中单个字段的值
+type SubscribeDataItem struct {
+	Value string `json:"value"`
+}
+
+// SubscribeMessage 订阅消息
+type SubscribeMessage struct {
+	// ToUser 接收者 openid
+	ToUser string `json:"touser"`
+	// TemplateID 订阅消息模板 ID
+	TemplateID string `json:"template_id"`
+	// Page 点击消息后跳转的页面（小程序页面路径，可带参数）
+	Page string `json:"page,omitempty"`
+	// MiniProgramState 跳转小程序类型：developer/trial/formal，默认 formal（仅小程序）
+	MiniProgramState string `json:"miniprogram_state,omitempty"`
+	// Lang 进入小程序查看的语言：zh_CN/en_US/zh_HK/zh_TW，默认 zh_CN（仅小程序）
+	Lang string `json:"lang,omitempty"`
+	// MiniProgram 跳转的小程序（仅服务号 bizsend 使用）
+	MiniProgram *SubscribeMiniProgram `json:"miniprogram,omitempty"`
+	// Data 模板内容，如 {"thing1": {"value": "..."}}
+	Data map[string]SubscribeDataItem `json:"data"`
+}
+
+// SubscribeMiniProgram 服务号订阅通知跳转的小程序
+type SubscribeMiniProgram struct {
+	AppID    string `json:"appid"`
+	PagePath string `json:"pagepath,omitempty"`
+}
+
+// SendSubscribeMessage 发送小程序订阅消息（message/subscribe/send）
+// 用户拒绝接收（43101）返回 CodeUserRefused，openid 不合法（40003）返回 CodeInvalidOpenID
+func (c *Client) SendSubscribeMessage(ctx context.Context, msg SubscribeMessage) (Code, error) {
+	return c.sendSubscribe(ctx, subscribeSendPath, msg)
+}
+
+// SendBizSubscribeMessage 发送服务号订阅通知（message/subscribe/bizsend）
+// 错误码处理同 SendSubscribeMessage
+func (c *Client) SendBizSubscribeMessage(ctx context.Context, msg SubscribeMessage) (Code, error) {
+	return c.sendSubscribe(ctx, subscribeBizSendPath, msg)
+}
+
+func (c *Client) sendSubscribe(ctx context.Context, path string, msg SubscribeMessage) (Code, error) {
+	if msg.ToUser == "" {
+		return CodeInvalidParam, fmt.Errorf("touser is required")
+	}
+	if msg.TemplateID == "" {
+		return CodeInvalidParam, fmt.Errorf("template_id is required")
+	}
+	if len(msg.Data) == 0 {
+		return CodeInvalidParam, fmt.Errorf("data is required")
+	}
+
+	code, err := c.postJSON(ctx, path, msg, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrCode {
+		case errCodeUserRefused:
+			code = CodeUserRefused
+		case errCodeInvalidOpenID:
+			code = CodeInvalidOpenID
+		}
+	}
+	return code, err
+}
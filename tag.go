@@ -0,0 +1,104 @@
+package wxgo
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	userUpdateRemarkPath = "/cgi-bin/user/info/updateremark"
+	tagCreatePath        = "/cgi-bin/tags/create"
+	tagGetPath           = "/cgi-bin/tags/get"
+	tagDeletePath        = "/cgi-bin/tags/delete"
+	tagBatchTaggingPath  = "/cgi-bin/tags/members/batchtagging"
+
+	// maxRemarkLength 备注名最大长度（字符）
+	maxRemarkLength = 30
+	// maxTagNameLength 标签名最大长度（字符）
+	maxTagNameLength = 30
+	// maxTagBatchSize 批量打标签单次最多 openid 数
+	maxTagBatchSize = 50
+)
+
+// Tag 用户标签
+type Tag struct {
+	// ID 标签 ID，由微信分配
+	ID int `json:"id"`
+	// Name 标签名
+	Name string `json:"name"`
+	// Count 此标签下的粉丝数（仅 ListTags 返回）
+	Count int `json:"count,omitempty"`
+}
+
+// UpdateUserRemark 设置用户备注名（最长 30 个字符）
+func (c *Client) UpdateUserRemark(ctx context.Context, openID, remark string) (Code, error) {
+	if openID == "" {
+		return CodeInvalidParam, fmt.Errorf("openid is required")
+	}
+	if utf8.RuneCountInString(remark) > maxRemarkLength {
+		return CodeInvalidParam, fmt.Errorf("remark length must be <=%d characters", maxRemarkLength)
+	}
+
+	body := map[string]any{
+		"openid": openID,
+		"remark": remark,
+	}
+	return c.postJSON(ctx, userUpdateRemarkPath, body, nil)
+}
+
+// CreateTag 创建标签（名称最长 30 个字符）
+func (c *Client) CreateTag(ctx context.Context, name string) (*Tag, Code, error) {
+	if name == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("tag name is required")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return nil, CodeInvalidParam, fmt.Errorf("tag name length must be <=%d characters", maxTagNameLength)
+	}
+
+	body := map[string]any{
+		"tag": map[string]any{"name": name},
+	}
+	var apiResp struct {
+		Tag Tag `json:"tag"`
+	}
+	if code, err := c.postJSON(ctx, tagCreatePath, body, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return &apiResp.Tag, CodeOK, nil
+}
+
+// ListTags 获取公众号已创建的全部标签
+func (c *Client) ListTags(ctx context.Context) ([]Tag, Code, error) {
+	var apiResp struct {
+		Tags []Tag `json:"tags"`
+	}
+	if code, err := c.getJSON(ctx, tagGetPath, nil, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return apiResp.Tags, CodeOK, nil
+}
+
+// DeleteTag 删除标签
+func (c *Client) DeleteTag(ctx context.Context, tagID int) (Code, error) {
+	body := map[string]any{
+		"tag": map[string]any{"id": tagID},
+	}
+	return c.postJSON(ctx, tagDeletePath, body, nil)
+}
+
+// TagUsers 批量为用户打标签，单次最多 50 个 openid
+func (c *Client) TagUsers(ctx context.Context, tagID int, openIDs []string) (Code, error) {
+	if len(openIDs) == 0 {
+		return CodeInvalidParam, fmt.Errorf("openid list is required")
+	}
+	if len(openIDs) > maxTagBatchSize {
+		return CodeInvalidParam, fmt.Errorf("openid list size must be <=%d, got %d", maxTagBatchSize, len(openIDs))
+	}
+
+	body := map[string]any{
+		"openid_list": openIDs,
+		"tagid":       tagID,
+	}
+	return c.postJSON(ctx, tagBatchTaggingPath, body, nil)
+}
@@ -0,0 +1,129 @@
+package wxgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	templateSetIndustryPath = "/cgi-bin/template/api_set_industry"
+	templateGetIndustryPath = "/cgi-bin/template/get_industry"
+	templateAddPath         = "/cgi-bin/template/api_add_template"
+	templateListPath        = "/cgi-bin/template/get_all_private_template"
+	templateDeletePath      = "/cgi-bin/template/del_private_template"
+
+	// minIndustryID/maxIndustryID 微信模板消息行业代码表的取值范围（1 IT科技/互联网|电子商务 … 41 其它/其它）
+	minIndustryID = 1
+	maxIndustryID = 41
+)
+
+// IndustryClass 行业分类：主行业与副行业名称
+type IndustryClass struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+}
+
+// Industry 公众号设置的模板消息所属行业
+type Industry struct {
+	// Primary 主营行业
+	Primary IndustryClass `json:"primary_industry"`
+	// Secondary 副营行业
+	Secondary IndustryClass `json:"secondary_industry"`
+}
+
+// TemplateInfo 公众号已添加的模板
+type TemplateInfo struct {
+	// TemplateID 模板 ID，发送模板消息时使用
+	TemplateID string `json:"template_id"`
+	Title      string `json:"title"`
+	// PrimaryIndustry 模板所属主行业的一级行业
+	PrimaryIndustry string `json:"primary_industry"`
+	// DeputyIndustry 模板所属主行业的二级行业
+	DeputyIndustry string `json:"deputy_industry"`
+	// Content 模板内容，含 {{keyword1.DATA}} 等占位符
+	Content string `json:"content"`
+	Example string `json:"example"`
+}
+
+// SetIndustry 设置模板消息所属行业（api_set_industry），id1 为主营行业、id2 为副营行业，取值见微信行业代码表（1-41）
+// 从模板库添加模板前需先设置行业；微信限制每月修改一次
+func (c *Client) SetIndustry(ctx context.Context, id1, id2 int) (Code, error) {
+	if err := validateIndustryID("industry_id1", id1); err != nil {
+		return CodeInvalidParam, err
+	}
+	if err := validateIndustryID("industry_id2", id2); err != nil {
+		return CodeInvalidParam, err
+	}
+
+	body := map[string]any{
+		"industry_id1": strconv.Itoa(id1),
+		"industry_id2": strconv.Itoa(id2),
+	}
+	return c.postJSON(ctx, templateSetIndustryPath, body, nil)
+}
+
+// GetIndustry 获取模板消息所属行业（get_industry）
+func (c *Client) GetIndustry(ctx context.Context) (*Industry, Code, error) {
+	var industry Industry
+	if code, err := c.getJSON(ctx, templateGetIndustryPath, nil, &industry); err != nil {
+		return nil, code, err
+	}
+	return &industry, CodeOK, nil
+}
+
+// validateIndustryID 校验行业代码是否在微信行业代码表范围内
+func validateIndustryID(name string, id int) error {
+	if id < minIndustryID || id > maxIndustryID {
+		return fmt.Errorf("%s must be within %d-%d, got %d", name, minIndustryID, maxIndustryID, id)
+	}
+	return nil
+}
+
+// AddTemplate 从模板库添加模板（api_add_template），返回值：(templateID, code, err)
+// templateIDShort 为模板库中模板的编号；keywordIDList 为选用的关键词 ID，为空时使用模板库默认关键词
+func (c *Client) AddTemplate(ctx context.Context, templateIDShort string, keywordIDList []int) (string, Code, error) {
+	if templateIDShort == "" {
+		return "", CodeInvalidParam, fmt.Errorf("template_id_short is required")
+	}
+
+	body := map[string]any{
+		"template_id_short": templateIDShort,
+	}
+	if len(keywordIDList) > 0 {
+		body["keyword_id_list"] = keywordIDList
+	}
+	var apiResp struct {
+		TemplateID string `json:"template_id"`
+	}
+	if code, err := c.postJSON(ctx, templateAddPath, body, &apiResp); err != nil {
+		return "", code, err
+	}
+	if apiResp.TemplateID == "" {
+		return "", CodeInvalidResponse, fmt.Errorf("decode %s response: missing template_id", templateAddPath)
+	}
+	return apiResp.TemplateID, CodeOK, nil
+}
+
+// ListTemplates 获取公众号已添加的全部模板（get_all_private_template）
+func (c *Client) ListTemplates(ctx context.Context) ([]TemplateInfo, Code, error) {
+	var apiResp struct {
+		TemplateList []TemplateInfo `json:"template_list"`
+	}
+	if code, err := c.getJSON(ctx, templateListPath, nil, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return apiResp.TemplateList, CodeOK, nil
+}
+
+// DeleteTemplate 删除公众号已添加的模板（del_private_template）
+func (c *Client) DeleteTemplate(ctx context.Context, templateID string) (Code, error) {
+	if templateID == "" {
+		return CodeInvalidParam, fmt.Errorf("template_id is required")
+	}
+
+	body := map[string]any{
+		"template_id": templateID,
+	}
+	return c.postJSON(ctx, templateDeletePath, body, nil)
+}
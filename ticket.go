@@ -0,0 +1,118 @@
+package wxgo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/qingfeng-studio/wxgo/internal/token"
+)
+
+const (
+	ticketGetPath = "/cgi-bin/ticket/getticket"
+	// workTicketGetPath 企业微信获取企业 jsapi_ticket 的接口
+	workTicketGetPath = "/cgi-bin/get_jsapi_ticket"
+
+	// ticketTypeJSAPI JS-SDK 使用的 jsapi_ticket
+	ticketTypeJSAPI = "jsapi"
+	// ticketTypeWxCard 卡券 JS-SDK（添加/领取卡券签名）使用的 api_ticket
+	ticketTypeWxCard = "wx_card"
+)
+
+// ticketFetcher 以当前 access_token 调用 path 获取指定类型的 ticket
+// 作为 TokenFetcher 交给 Manager，ticket 放在 TokenInfo.AccessToken 中缓存
+type ticketFetcher struct {
+	c     *Client
+	path  string
+	query url.Values
+}
+
+// ticketEndpoint 返回 provider 下获取 ticketType 类型 ticket 的接口与参数
+// 公众号/小程序（含稳定版 token）调用 ticket/getticket；企业微信仅支持 jsapi，调用 get_jsapi_ticket；第三方平台的 component_access_token 无法获取 ticket
+func ticketEndpoint(provider Provider, ticketType string) (string, url.Values, error) {
+	switch provider {
+	case "", ProviderMP, ProviderStable:
+		return ticketGetPath, url.Values{"type": {ticketType}}, nil
+	case ProviderWorkWeChat:
+		if ticketType == ticketTypeJSAPI {
+			return workTicketGetPath, nil, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%s ticket is not supported for provider %s", ticketType, provider)
+}
+
+// Fetch 获取 ticket；access_token 失效（40001）时由 doAPI 自动刷新重试
+func (f ticketFetcher) Fetch(ctx context.Context) (*token.TokenInfo, Code, error) {
+	var apiResp struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}
+	if code, err := f.c.getJSON(ctx, f.path, f.query, &apiResp); err != nil {
+		return nil, code, err
+	}
+	if apiResp.Ticket == "" {
+		return nil, CodeInvalidResponse, fmt.Errorf("decode %s response: missing ticket", f.path)
+	}
+	return &token.TokenInfo{AccessToken: apiResp.Ticket, ExpiresIn: apiResp.ExpiresIn}, CodeOK, nil
+}
+
+// ticketManager 返回 ticketType 类型 ticket 的 Manager，首次使用时创建
+// 与 access_token 共用缓存、分布式锁、提前刷新与后台刷新配置，缓存 key 为 {prefix}:{ticketType}_ticket:{appID}
+// Metrics 仅在实现 CredentialMetrics 时按凭据名上报，不与 access_token 混计
+func (c *Client) ticketManager(ticketType string) (*token.Manager, Code, error) {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+
+	if m, ok := c.credentials[ticketType]; ok {
+		return m, CodeOK, nil
+	}
+	path, query, err := ticketEndpoint(c.tokenCfg.Provider, ticketType)
+	if err != nil {
+		return nil, CodeInvalidParam, err
+	}
+	cfg := c.tokenCfg
+	cfg.Credential = ticketType + "_ticket"
+	cfg.Fetcher = ticketFetcher{c: c, path: path, query: query}
+	cfg.Metrics = token.MetricsForCredential(c.tokenCfg.Metrics, cfg.Credential)
+	// Close 之后仍可同步获取，但不再启动后台刷新协程
+	cfg.BackgroundRefresh = cfg.BackgroundRefresh && !c.closed
+	m, err := token.NewManager(&cfg)
+	if err != nil {
+		return nil, CodeUnknown, fmt.Errorf("create %s ticket manager: %w", ticketType, err)
+	}
+	if c.credentials == nil {
+		c.credentials = make(map[string]*token.Manager)
+	}
+	c.credentials[ticketType] = m
+	return m, CodeOK, nil
+}
+
+// getTicket 获取 ticketType 类型的 ticket，缓存未命中或进入提前刷新窗口时获取新 ticket
+func (c *Client) getTicket(ctx context.Context, ticketType string) (string, Code, error) {
+	m, code, err := c.ticketManager(ticketType)
+	if err != nil {
+		return "", code, err
+	}
+	return m.GetAccessToken(ctx)
+}
+
+// GetJSAPITicket 获取 JS-SDK 使用的 jsapi_ticket，返回值：(ticket, code, err)
+// 有效期同为 7200 秒，与 access_token 共用缓存、分布式锁、提前刷新与后台刷新逻辑；缓存 key 为 {prefix}:jsapi_ticket:{appID}
+func (c *Client) GetJSAPITicket(ctx context.Context) (string, Code, error) {
+	return c.getTicket(ctx, ticketTypeJSAPI)
+}
+
+// GetWxCardTicket 获取卡券使用的 wx_card 类型 api_ticket，返回值：(ticket, code, err)
+// 缓存、锁与刷新逻辑同 GetJSAPITicket；缓存 key 为 {prefix}:wx_card_ticket:{appID}
+func (c *Client) GetWxCardTicket(ctx context.Context) (string, Code, error) {
+	return c.getTicket(ctx, ticketTypeWxCard)
+}
+
+// BuildJSConfig 获取 jsapi_ticket 并为页面 URL 生成 wx.config 签名参数
+func (c *Client) BuildJSConfig(ctx context.Context, pageURL string) (*JSConfig, Code, error) {
+	ticket, code, err := c.GetJSAPITicket(ctx)
+	if err != nil {
+		return nil, code, err
+	}
+	return c.SignJSConfig(ticket, pageURL)
+}
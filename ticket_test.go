@@ -0,0 +1,152 @@
+package wxgo_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+// countingMetrics 统计 TokenFetch 次数
+type countingMetrics struct {
+	fetches atomic.Int32
+}
+
+func (m *countingMetrics) CacheHit()                           {}
+func (m *countingMetrics) CacheMiss()                          {}
+func (m *countingMetrics) TokenFetch(wxgo.Code, time.Duration) { m.fetches.Add(1) }
+func (m *countingMetrics) LockAcquire(time.Duration, error)    {}
+
+// credentialMetrics 实现 CredentialMetrics，按凭据名分别计数
+type credentialMetrics struct {
+	countingMetrics
+	mu          sync.Mutex
+	credentials map[string]*countingMetrics
+}
+
+func (m *credentialMetrics) ForCredential(credential string) wxgo.Metrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.credentials == nil {
+		m.credentials = make(map[string]*countingMetrics)
+	}
+	if _, ok := m.credentials[credential]; !ok {
+		m.credentials[credential] = &countingMetrics{}
+	}
+	return m.credentials[credential]
+}
+
+// newTicketServer 模拟公众号与企业微信的 token、ticket 接口，记录被调用的 ticket 路径
+func newTicketServer(t *testing.T, paths *sync.Map) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cgi-bin/token", "/cgi-bin/gettoken":
+			fmt.Fprint(w, `{"access_token":"tk","expires_in":7200}`)
+		case "/cgi-bin/ticket/getticket", "/cgi-bin/get_jsapi_ticket":
+			paths.Store(r.URL.Path+"?type="+r.URL.Query().Get("type"), true)
+			fmt.Fprint(w, `{"errcode":0,"ticket":"ticket-1","expires_in":7200}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTicketEndpointByProvider(t *testing.T) {
+	var paths sync.Map
+	srv := newTicketServer(t, &paths)
+
+	work, err := wxgo.NewClient(wxgo.Config{Provider: wxgo.ProviderWorkWeChat, CorpID: "ww_test", CorpSecret: "secret", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer work.Close()
+
+	ticket, code, err := work.GetJSAPITicket(context.Background())
+	if err != nil || ticket != "ticket-1" {
+		t.Fatalf("work GetJSAPITicket = %q, %v, %v", ticket, code, err)
+	}
+	if _, ok := paths.Load("/cgi-bin/get_jsapi_ticket?type="); !ok {
+		t.Fatal("work jsapi_ticket was not fetched from /cgi-bin/get_jsapi_ticket")
+	}
+	if _, code, err := work.GetWxCardTicket(context.Background()); err == nil || code != wxgo.CodeInvalidParam {
+		t.Fatalf("work GetWxCardTicket: code=%v err=%v, want CodeInvalidParam", code, err)
+	}
+
+	component, err := wxgo.NewClient(wxgo.Config{
+		Provider:              wxgo.ProviderComponent,
+		AppID:                 "wx_component",
+		AppSecret:             "secret",
+		ComponentVerifyTicket: func(context.Context) (string, error) { return "verify", nil },
+		BaseURL:               srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer component.Close()
+	if _, code, err := component.GetJSAPITicket(context.Background()); err == nil || code != wxgo.CodeInvalidParam {
+		t.Fatalf("component GetJSAPITicket: code=%v err=%v, want CodeInvalidParam", code, err)
+	}
+
+	mp, err := wxgo.NewClient(wxgo.Config{AppID: "wx_mp", AppSecret: "secret", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer mp.Close()
+	if _, _, err := mp.GetWxCardTicket(context.Background()); err != nil {
+		t.Fatalf("mp GetWxCardTicket: %v", err)
+	}
+	if _, ok := paths.Load("/cgi-bin/ticket/getticket?type=wx_card"); !ok {
+		t.Fatal("mp wx_card ticket was not fetched from /cgi-bin/ticket/getticket")
+	}
+}
+
+func TestTicketMetricsSeparateFromAccessToken(t *testing.T) {
+	var paths sync.Map
+	srv := newTicketServer(t, &paths)
+
+	t.Run("plain metrics", func(t *testing.T) {
+		m := &countingMetrics{}
+		c, err := wxgo.NewClient(wxgo.Config{AppID: "wx_plain", AppSecret: "secret", BaseURL: srv.URL, Metrics: m})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		defer c.Close()
+		if _, _, err := c.GetJSAPITicket(context.Background()); err != nil {
+			t.Fatalf("GetJSAPITicket: %v", err)
+		}
+		// 仅 access_token 的获取计入；ticket 不上报
+		if got := m.fetches.Load(); got != 1 {
+			t.Fatalf("TokenFetch calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("credential metrics", func(t *testing.T) {
+		m := &credentialMetrics{}
+		c, err := wxgo.NewClient(wxgo.Config{AppID: "wx_labelled", AppSecret: "secret", BaseURL: srv.URL, Metrics: m})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		defer c.Close()
+		if _, _, err := c.GetJSAPITicket(context.Background()); err != nil {
+			t.Fatalf("GetJSAPITicket: %v", err)
+		}
+		if got := m.fetches.Load(); got != 1 {
+			t.Fatalf("access_token TokenFetch calls = %d, want 1", got)
+		}
+		m.mu.Lock()
+		ticket := m.credentials["jsapi_ticket"]
+		m.mu.Unlock()
+		if ticket == nil || ticket.fetches.Load() != 1 {
+			t.Fatalf("jsapi_ticket metrics = %+v, want one fetch", ticket)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package wxgo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/qingfeng-studio/wxgo/internal/token"
+)
+
+// accessTokenKey ctx 中预先获取的 access_token 的 key，按 AppID 区分
+type accessTokenKey struct {
+	appID string
+}
+
+// WithAccessToken 返回携带 appID 的 access_token 的 ctx：该 AppID 的 Client 的 GetAccessToken 与所有业务接口直接使用它，跳过缓存读取与向微信获取
+// 其他 AppID 的 Client（如 Registry 中的其他租户）不受影响，同一 ctx 可分别携带多个应用的 token
+// 适用于集中式 token 服务（broker）拓扑：由一个服务获取 token，随请求下发给其他服务
+// 该 token 不会写入缓存；微信返回 40001/42001 时也不会自动刷新重试，由调用方重新获取后重试
+// 企业微信的 appID 为 corpid；appID 或 accessToken 为空时返回原 ctx
+func WithAccessToken(ctx context.Context, appID, accessToken string) context.Context {
+	appID = strings.TrimSpace(appID)
+	if appID == "" || accessToken == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, accessTokenKey{appID: appID}, accessToken)
+}
+
+// AccessTokenFromContext 返回 WithAccessToken 为 appID 放入 ctx 的 access_token
+func AccessTokenFromContext(ctx context.Context, appID string) (string, bool) {
+	tk, ok := ctx.Value(accessTokenKey{appID: strings.TrimSpace(appID)}).(string)
+	return tk, ok && tk != ""
+}
+
+// contextAccessToken 返回 ctx 中为本 Client 的 AppID 携带的 access_token
+func (c *Client) contextAccessToken(ctx context.Context) (string, bool) {
+	return AccessTokenFromContext(ctx, c.tokenCfg.AppID)
+}
+
+// WithLockStrategy 返回携带分布式锁策略的 ctx，GetAccessToken 等调用仅对这一次使用该策略
+// 例如启动预热或健康检查传入 DistLockOff，只用本地互斥，不在争抢激烈的分布式锁上阻塞
+// DistLockOn 要求本次持有分布式锁，客户端未启用分布式锁时返回 CodeLock；DistLockAuto 沿用 Config.DistLockStrategy
+func WithLockStrategy(ctx context.Context, strategy DistLockStrategy) context.Context {
+	return token.WithLockStrategy(ctx, strategy)
+}
@@ -0,0 +1,38 @@
+package wxgo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+func TestWithAccessTokenScopedByAppID(t *testing.T) {
+	a, err := wxgo.New("wx_a", "secret_a", wxgo.WithMock())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer a.Close()
+	b, err := wxgo.New("wx_b", "secret_b", wxgo.WithMock())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer b.Close()
+
+	ctx := wxgo.WithAccessToken(context.Background(), "wx_a", "token_for_a")
+
+	if tk, _, err := a.GetAccessToken(ctx); err != nil || tk != "token_for_a" {
+		t.Fatalf("app A token = %q, %v; want override", tk, err)
+	}
+	if tk, _, err := b.GetAccessToken(ctx); err != nil || tk != wxgo.MockAccessToken {
+		t.Fatalf("app B token = %q, %v; want its own token, not app A's override", tk, err)
+	}
+
+	ctx = wxgo.WithAccessToken(ctx, "wx_b", "token_for_b")
+	if tk, _, _ := b.GetAccessToken(ctx); tk != "token_for_b" {
+		t.Fatalf("app B token = %q, want token_for_b", tk)
+	}
+	if tk, _, _ := a.GetAccessToken(ctx); tk != "token_for_a" {
+		t.Fatalf("app A token = %q, want token_for_a", tk)
+	}
+}
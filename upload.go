@@ -0,0 +1,149 @@
+package wxgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+)
+
+// progressReader 读取时通过 onProgress 报告累计已读取（即已发送）的字节数
+type progressReader struct {
+	r          io.Reader
+	sent       int64
+	total      int64
+	onProgress func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.onProgress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// uploadContent 上传的文件内容：可 Seek 时记录起始位置，重发时回到该位置重新读取
+type uploadContent struct {
+	r io.Reader
+	// seeker 为 nil 表示不可重放，只能发送一次
+	seeker io.Seeker
+	start  int64
+	// size 内容字节数，-1 表示未知
+	size       int64
+	opened     bool
+	onProgress func(sent, total int64)
+}
+
+// newUploadContent 包装上传内容并计算大小：*os.File 取文件大小，其余 io.Seeker 通过 Seek 计算，否则为 -1
+// 未设置 onProgress 且 r 不可 Seek 时先读入内存，保证 token 失效重试时仍可重发
+func newUploadContent(r io.Reader, onProgress func(sent, total int64)) (*uploadContent, error) {
+	content := &uploadContent{r: r, size: -1, onProgress: onProgress}
+	// 管道、标准输入等 *os.File 虽实现 io.Seeker，但 Seek 会失败，按不可 Seek 处理
+	if s, ok := r.(io.Seeker); ok {
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			size, err := seekableSize(r, s, start)
+			if err != nil {
+				return nil, err
+			}
+			content.seeker, content.start, content.size = s, start, size
+		}
+	}
+	if content.seeker == nil && onProgress == nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("read upload content: %w", err)
+		}
+		br := bytes.NewReader(data)
+		content.r, content.seeker, content.size = br, br, int64(len(data))
+	}
+	return content, nil
+}
+
+// seekableSize 返回从 start 到末尾的字节数，并把读取位置恢复到 start
+func seekableSize(r io.Reader, s io.Seeker, start int64) (int64, error) {
+	if f, ok := r.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size() - start, nil
+		}
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("seek upload content: %w", err)
+	}
+	if _, err := s.Seek(start, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("seek upload content: %w", err)
+	}
+	return end - start, nil
+}
+
+// open 返回从起始位置读取内容的 reader；大小已知时最多读取 size 字节，与 Content-Length 保持一致
+func (u *uploadContent) open() (io.Reader, error) {
+	if u.opened {
+		if u.seeker == nil {
+			return nil, errors.New("upload reader is not seekable and cannot be resent")
+		}
+		if _, err := u.seeker.Seek(u.start, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewind upload content: %w", err)
+		}
+	}
+	u.opened = true
+
+	r := u.r
+	if u.size >= 0 {
+		r = io.LimitReader(r, u.size)
+	}
+	if u.onProgress == nil {
+		return r, nil
+	}
+	return &progressReader{r: r, total: u.size, onProgress: u.onProgress}, nil
+}
+
+// redirectWriter 转发写入到 w，用于把 multipart 文件头与尾部分别写入不同的缓冲区
+type redirectWriter struct {
+	w io.Writer
+}
+
+func (r *redirectWriter) Write(p []byte) (int, error) {
+	return r.w.Write(p)
+}
+
+// newMultipartUpload 生成上传文件的 multipart 请求体，返回请求体与 Content-Type
+// 文件头和 fields 等尾部字段预先编码，文件内容在发送时流式读取，不整体读入内存
+func newMultipartUpload(fieldName, filename string, content *uploadContent, fields map[string]string) (*requestBody, string, error) {
+	var head, tail bytes.Buffer
+	w := &redirectWriter{w: &head}
+	mw := multipart.NewWriter(w)
+	if _, err := mw.CreateFormFile(fieldName, filename); err != nil {
+		return nil, "", fmt.Errorf("create multipart file: %w", err)
+	}
+	w.w = &tail
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			return nil, "", fmt.Errorf("write multipart field %s: %w", name, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	headBytes, tailBytes := head.Bytes(), tail.Bytes()
+	size := int64(-1)
+	if content.size >= 0 {
+		size = int64(len(headBytes)) + content.size + int64(len(tailBytes))
+	}
+	body := &requestBody{
+		open: func() (io.Reader, error) {
+			r, err := content.open()
+			if err != nil {
+				return nil, err
+			}
+			return io.MultiReader(bytes.NewReader(headBytes), r, bytes.NewReader(tailBytes)), nil
+		},
+		size: size,
+	}
+	return body, mw.FormDataContentType(), nil
+}
@@ -0,0 +1,181 @@
+package wxgo_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qingfeng-studio/wxgo"
+	"github.com/qingfeng-studio/wxgo/wxtest"
+)
+
+const materialAddPath = "/cgi-bin/material/add_material"
+
+// progressRecorder 记录 onProgress 收到的 (sent, total) 序列
+type progressRecorder struct {
+	sent, total []int64
+}
+
+func (p *progressRecorder) record(sent, total int64) {
+	p.sent = append(p.sent, sent)
+	p.total = append(p.total, total)
+}
+
+// onlyReader 隐藏底层 reader 的 io.Seeker 等接口
+type onlyReader struct {
+	io.Reader
+}
+
+func newUploadClient(t *testing.T) (*wxtest.Server, *wxgo.Client) {
+	t.Helper()
+	srv := wxtest.NewServer()
+	t.Cleanup(srv.Close)
+	c, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return srv, c
+}
+
+// invalidateServerToken 先取得 token 再让服务端换发新 token，使下一次上传先收到 40001
+func invalidateServerToken(t *testing.T, srv *wxtest.Server, c *wxgo.Client) {
+	t.Helper()
+	if _, _, err := c.GetAccessToken(context.Background()); err != nil {
+		t.Fatalf("GetAccessToken: %v", err)
+	}
+	srv.SetAccessToken("rotated_access_token")
+}
+
+func TestUploadProgressTotals(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 100<<10)
+	path := filepath.Join(t.TempDir(), "image.jpg")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		open  func(t *testing.T) io.Reader
+		total int64
+	}{
+		{
+			name: "file",
+			open: func(t *testing.T) io.Reader {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("Open: %v", err)
+				}
+				t.Cleanup(func() { f.Close() })
+				return f
+			},
+			total: int64(len(data)),
+		},
+		{
+			name:  "seeker",
+			open:  func(*testing.T) io.Reader { return bytes.NewReader(data) },
+			total: int64(len(data)),
+		},
+		{
+			name:  "non-seeker",
+			open:  func(*testing.T) io.Reader { return onlyReader{bytes.NewReader(data)} },
+			total: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, c := newUploadClient(t)
+
+			var progress progressRecorder
+			_, code, err := c.AddPermanentMaterialWithProgress(context.Background(), wxgo.MaterialImage, "image.jpg", tt.open(t), progress.record)
+			if err != nil || code != wxgo.CodeOK {
+				t.Fatalf("upload: code=%v err=%v", code, err)
+			}
+			if srv.Calls(materialAddPath) != 1 {
+				t.Fatalf("material/add_material called %d times, want 1", srv.Calls(materialAddPath))
+			}
+			if len(progress.sent) == 0 {
+				t.Fatal("onProgress was not called")
+			}
+			for i := range progress.sent {
+				if progress.total[i] != tt.total {
+					t.Fatalf("total[%d] = %d, want %d", i, progress.total[i], tt.total)
+				}
+				if i > 0 && progress.sent[i] <= progress.sent[i-1] {
+					t.Fatalf("sent not increasing: %v", progress.sent)
+				}
+			}
+			if last := progress.sent[len(progress.sent)-1]; last != int64(len(data)) {
+				t.Fatalf("final sent = %d, want %d", last, len(data))
+			}
+		})
+	}
+}
+
+func TestUploadRewindsOnInvalidTokenRetry(t *testing.T) {
+	srv, c := newUploadClient(t)
+	invalidateServerToken(t, srv, c)
+
+	data := bytes.Repeat([]byte("y"), 64<<10)
+	var progress progressRecorder
+	_, code, err := c.AddPermanentMaterialWithProgress(context.Background(), wxgo.MaterialImage, "image.jpg", bytes.NewReader(data), progress.record)
+	if err != nil || code != wxgo.CodeOK {
+		t.Fatalf("upload: code=%v err=%v", code, err)
+	}
+	if srv.Calls(materialAddPath) != 2 {
+		t.Fatalf("material/add_material called %d times, want 2 (40001 then retry)", srv.Calls(materialAddPath))
+	}
+
+	// 重发时回到起始位置，sent 从 0 重新计数；两次都发送完整内容
+	restarts, total := 0, int64(len(data))
+	for i := range progress.sent {
+		if progress.total[i] != total {
+			t.Fatalf("total[%d] = %d, want %d", i, progress.total[i], total)
+		}
+		if i > 0 && progress.sent[i] < progress.sent[i-1] {
+			if progress.sent[i-1] != total {
+				t.Fatalf("first attempt stopped at %d of %d bytes", progress.sent[i-1], total)
+			}
+			restarts++
+		}
+	}
+	if restarts != 1 || progress.sent[len(progress.sent)-1] != total {
+		t.Fatalf("progress = %v, want one full pass, a restart and a second full pass", progress.sent)
+	}
+}
+
+func TestUploadNonSeekableRetry(t *testing.T) {
+	data := bytes.Repeat([]byte("z"), 16<<10)
+
+	t.Run("with progress", func(t *testing.T) {
+		srv, c := newUploadClient(t)
+		invalidateServerToken(t, srv, c)
+
+		var progress progressRecorder
+		_, code, err := c.AddPermanentMaterialWithProgress(context.Background(), wxgo.MaterialImage, "image.jpg", onlyReader{bytes.NewReader(data)}, progress.record)
+		if err == nil || !strings.Contains(err.Error(), "cannot be resent") {
+			t.Fatalf("upload: code=%v err=%v, want a cannot-be-resent error", code, err)
+		}
+		if srv.Calls(materialAddPath) != 1 {
+			t.Fatalf("material/add_material called %d times, want 1", srv.Calls(materialAddPath))
+		}
+	})
+
+	// 未设置 onProgress 时不可 Seek 的内容先读入内存，仍可重发
+	t.Run("without progress", func(t *testing.T) {
+		srv, c := newUploadClient(t)
+		invalidateServerToken(t, srv, c)
+
+		_, code, err := c.AddPermanentMaterial(context.Background(), wxgo.MaterialImage, "image.jpg", onlyReader{bytes.NewReader(data)})
+		if err != nil || code != wxgo.CodeOK {
+			t.Fatalf("upload: code=%v err=%v", code, err)
+		}
+		if srv.Calls(materialAddPath) != 2 {
+			t.Fatalf("material/add_material called %d times, want 2", srv.Calls(materialAddPath))
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package wxgo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	userGetPath          = "/cgi-bin/user/get"
+	userInfoPath         = "/cgi-bin/user/info"
+	userInfoBatchGetPath = "/cgi-bin/user/info/batchget"
+
+	// maxUserInfoBatchSize 批量获取用户信息单次最多 openid 数
+	maxUserInfoBatchSize = 100
+)
+
+// UserInfo 用户基本信息（user/info）
+// 用户未关注时 Subscribe 为 0，除 OpenID、UnionID 外的字段为空
+type UserInfo struct {
+	// Subscribe 是否关注公众号：1 已关注，0 未关注
+	Subscribe int    `json:"subscribe"`
+	OpenID    string `json:"openid"`
+	Language  string `json:"language,omitempty"`
+	// SubscribeTime 最后一次关注时间（Unix 秒）
+	SubscribeTime int64 `json:"subscribe_time,omitempty"`
+	// UnionID 开放平台下的统一用户标识；仅当公众号已绑定到微信开放平台帐号时返回
+	UnionID   string `json:"unionid,omitempty"`
+	Remark    string `json:"remark,omitempty"`
+	TagIDList []int  `json:"tagid_list,omitempty"`
+	// SubscribeScene 关注渠道，如 ADD_SCENE_QR_CODE（扫码）、ADD_SCENE_SEARCH（搜索）
+	SubscribeScene string `json:"subscribe_scene,omitempty"`
+	// QRScene/QRSceneStr 扫码关注时的二维码场景值
+	QRScene    int    `json:"qr_scene,omitempty"`
+	QRSceneStr string `json:"qr_scene_str,omitempty"`
+}
+
+// GetUserInfo 获取用户基本信息（user/info）
+func (c *Client) GetUserInfo(ctx context.Context, openID string) (*UserInfo, Code, error) {
+	if openID == "" {
+		return nil, CodeInvalidParam, fmt.Errorf("openid is required")
+	}
+
+	query := url.Values{"openid": {openID}, "lang": {"zh_CN"}}
+	var info UserInfo
+	if code, err := c.getJSON(ctx, userInfoPath, query, &info); err != nil {
+		return nil, code, err
+	}
+	return &info, CodeOK, nil
+}
+
+// BatchGetUserInfo 批量获取用户基本信息（user/info/batchget），单次最多 100 个 openid
+// 结果顺序与微信返回一致；UnionID 的返回条件同 GetUserInfo
+func (c *Client) BatchGetUserInfo(ctx context.Context, openIDs []string) ([]UserInfo, Code, error) {
+	if len(openIDs) == 0 {
+		return nil, CodeInvalidParam, fmt.Errorf("openid list is required")
+	}
+	if len(openIDs) > maxUserInfoBatchSize {
+		return nil, CodeInvalidParam, fmt.Errorf("openid list size must be <=%d", maxUserInfoBatchSize)
+	}
+
+	userList := make([]map[string]string, 0, len(openIDs))
+	for _, openID := range openIDs {
+		if openID == "" {
+			return nil, CodeInvalidParam, fmt.Errorf("openid list contains an empty openid")
+		}
+		userList = append(userList, map[string]string{"openid": openID, "lang": "zh_CN"})
+	}
+	body := map[string]any{
+		"user_list": userList,
+	}
+	var apiResp struct {
+		UserInfoList []UserInfo `json:"user_info_list"`
+	}
+	if code, err := c.postJSON(ctx, userInfoBatchGetPath, body, &apiResp); err != nil {
+		return nil, code, err
+	}
+	return apiResp.UserInfoList, CodeOK, nil
+}
+
+// OpenIDToUnionID 通过 user/info 查询 openid 对应的 unionid，用户取消关注后仍可查询
+// 前提是公众号已绑定到微信开放平台帐号，否则微信不返回 unionid，此时返回 CodeUnionIDUnavailable
+func (c *Client) OpenIDToUnionID(ctx context.Context, openID string) (string, Code, error) {
+	info, code, err := c.GetUserInfo(ctx, openID)
+	if err != nil {
+		return "", code, err
+	}
+	if info.UnionID == "" {
+		return "", CodeUnionIDUnavailable, fmt.Errorf("no unionid for openid %s: the account must be bound to an open platform account", openID)
+	}
+	return info.UnionID, CodeOK, nil
+}
+
+// FollowerPage 关注者列表分页结果
+type FollowerPage struct {
+	// Total 关注者总数
+	Total int
+	// Count 本页拉取的 OpenID 数量（最多 10000）
+	Count int
+	// OpenIDs 本页的 OpenID 列表
+	OpenIDs []string
+	// NextOpenID 下一页起始 OpenID；为空表示已拉取完毕
+	NextOpenID string
+}
+
+// GetFollowers 获取关注者列表（单页）
+// nextOpenID 为空时从头开始拉取
+func (c *Client) GetFollowers(ctx context.Context, nextOpenID string) (*FollowerPage, Code, error) {
+	query := url.Values{}
+	if nextOpenID != "" {
+		query.Set("next_openid", nextOpenID)
+	}
+
+	var apiResp struct {
+		Total int `json:"total"`
+		Count int `json:"count"`
+		Data  struct {
+			OpenID []string `json:"openid"`
+		} `json:"data"`
+		NextOpenID string `json:"next_openid"`
+	}
+	if code, err := c.getJSON(ctx, userGetPath, query, &apiResp); err != nil {
+		return nil, code, err
+	}
+
+	return &FollowerPage{
+		Total:      apiResp.Total,
+		Count:      apiResp.Count,
+		OpenIDs:    apiResp.Data.OpenID,
+		NextOpenID: apiResp.NextOpenID,
+	}, CodeOK, nil
+}
+
+// IterateFollowers 逐页遍历全部关注者，每页调用一次 fn
+// 页与页之间检查 ctx；某页失败时直接返回错误，已交给 fn 的页不受影响
+// fn 返回错误时停止遍历并原样返回
+func (c *Client) IterateFollowers(ctx context.Context, fn func(openIDs []string) error) error {
+	next := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, code, err := c.GetFollowers(ctx, next)
+		if err != nil {
+			return fmt.Errorf("get followers (next_openid=%q, code=%s): %w", next, code, err)
+		}
+
+		// 最后一页之后微信返回 count=0，不再回调
+		if page.Count == 0 || len(page.OpenIDs) == 0 {
+			return nil
+		}
+		if err := fn(page.OpenIDs); err != nil {
+			return err
+		}
+
+		if page.NextOpenID == "" || page.NextOpenID == next {
+			return nil
+		}
+		next = page.NextOpenID
+	}
+}
@@ -0,0 +1,201 @@
+// Package wxtest 提供模拟微信接口的 HTTP 服务，便于测试使用 wxgo 的代码
+// 服务按微信的 JSON 结构响应 token、二维码等已封装的接口，可按路径配置返回内容或 errcode
+package wxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/qingfeng-studio/wxgo"
+)
+
+const (
+	// DefaultAppID NewClient 默认使用的 AppID
+	DefaultAppID = "wxtest_appid"
+	// DefaultAppSecret NewClient 默认使用的 AppSecret
+	DefaultAppSecret = "wxtest_secret"
+	// DefaultAccessToken 默认下发的 access_token
+	DefaultAccessToken = "wxtest_access_token"
+	// DefaultQRCodeTicket 生成二维码默认返回的 ticket
+	DefaultQRCodeTicket = "wxtest_qrcode_ticket"
+
+	tokenPath    = "/cgi-bin/token"
+	qrCreatePath = "/cgi-bin/qrcode/create"
+	qrShowPath   = "/cgi-bin/showqrcode"
+)
+
+// Response 某个路径的固定响应
+type Response struct {
+	// Status HTTP 状态码，默认 200
+	Status int
+	// Header 额外响应 Header
+	Header http.Header
+	// Body 响应体：[]byte/string 原样返回，其余类型序列化为 JSON
+	Body any
+}
+
+// Server 模拟微信接口的测试服务
+// 未配置的路径返回 {"errcode":0,"errmsg":"ok"}；需要 access_token 的接口校验 token，不匹配时返回 40001
+// 二维码图片下载（QRCodeOption.Download）经 MPBaseURL 同样指向本服务，默认返回 PNG 文件头占位
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	accessToken string
+	expiresIn   int
+	responses   map[string]Response
+	calls       map[string]int
+}
+
+// Option Server 可选配置
+type Option func(*Server)
+
+// WithAccessToken 设置 /cgi-bin/token 下发的 token 与有效期（秒）
+func WithAccessToken(accessToken string, expiresIn int) Option {
+	return func(s *Server) {
+		s.accessToken = accessToken
+		s.expiresIn = expiresIn
+	}
+}
+
+// WithResponse 为 path 设置固定响应，覆盖默认行为（包括 /cgi-bin/token）
+func WithResponse(path string, resp Response) Option {
+	return func(s *Server) {
+		s.responses[path] = resp
+	}
+}
+
+// WithErrCode 使 path 返回指定的微信 errcode
+func WithErrCode(path string, errCode int, errMsg string) Option {
+	return WithResponse(path, Response{Body: map[string]any{"errcode": errCode, "errmsg": errMsg}})
+}
+
+// NewServer 启动模拟服务，使用完毕后调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		accessToken: DefaultAccessToken,
+		expiresIn:   7200,
+		responses:   make(map[string]Response),
+		calls:       make(map[string]int),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient 创建指向本服务（BaseURL 与 MPBaseURL 覆盖）的 wxgo.Client；opts 在默认配置之后应用
+func (s *Server) NewClient(opts ...wxgo.Option) (*wxgo.Client, error) {
+	base := func(c *wxgo.Config) {
+		c.BaseURL = s.URL
+		c.MPBaseURL = s.URL
+	}
+	return wxgo.New(DefaultAppID, DefaultAppSecret, append([]wxgo.Option{base}, opts...)...)
+}
+
+// SetResponse 运行期间为 path 设置固定响应
+func (s *Server) SetResponse(path string, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = resp
+}
+
+// ClearResponse 恢复 path 的默认行为
+func (s *Server) ClearResponse(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, path)
+}
+
+// SetAccessToken 更换下发的 token；旧 token 之后调用接口会返回 40001
+func (s *Server) SetAccessToken(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = accessToken
+}
+
+// Calls 返回 path 被请求的次数
+func (s *Server) Calls(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls[r.URL.Path]++
+	resp, ok := s.responses[r.URL.Path]
+	accessToken, expiresIn := s.accessToken, s.expiresIn
+	s.mu.Unlock()
+
+	if ok {
+		writeResponse(w, resp)
+		return
+	}
+
+	switch {
+	case r.URL.Path == tokenPath || strings.HasSuffix(r.URL.Path, "/gettoken"):
+		writeJSON(w, map[string]any{"access_token": accessToken, "expires_in": expiresIn})
+		return
+	case r.URL.Path == qrShowPath:
+		writeResponse(w, Response{Header: http.Header{"Content-Type": {"image/png"}}, Body: []byte("\x89PNG\r\n\x1a\n")})
+		return
+	}
+
+	if r.URL.Query().Get("access_token") != accessToken {
+		writeJSON(w, map[string]any{"errcode": 40001, "errmsg": "invalid credential, access_token is invalid or not latest"})
+		return
+	}
+
+	switch r.URL.Path {
+	case qrCreatePath:
+		writeJSON(w, map[string]any{
+			"ticket":         DefaultQRCodeTicket,
+			"expire_seconds": 0,
+			"url":            "http://weixin.qq.com/q/wxtest",
+		})
+	default:
+		writeJSON(w, map[string]any{"errcode": 0, "errmsg": "ok"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	writeResponse(w, Response{Body: v})
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+
+	var data []byte
+	switch body := resp.Body.(type) {
+	case []byte:
+		data = body
+	case string:
+		data = []byte(body)
+	case nil:
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+	}
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}